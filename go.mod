@@ -1,3 +1,5 @@
 module github.com/uug-ai/utils
 
 go 1.24.5
+
+require go.mongodb.org/mongo-driver/v2 v2.5.0
@@ -0,0 +1,191 @@
+package cron
+
+import (
+	"time"
+)
+
+// searchDays bounds the search for a matching day. Nine years covers a February
+// 29th schedule across the skipped leap day in 2100.
+const searchDays = 366 * 9
+
+// maxSegments bounds the number of DST periods walked through in Next and Prev.
+const maxSegments = 1000
+
+// Next returns the first time strictly after after at which the expression fires,
+// in the expression's location. It returns the zero time when the expression never
+// fires again (e.g. "0 0 30 2 *").
+//
+// Across DST changes it behaves like Vixie cron: a wall clock time skipped when the
+// clocks go forward fires once at the moment of the jump, and a wall clock time
+// repeated when the clocks go back fires only at its first occurrence, unless the
+// hour field is a wildcard, in which case it fires in both.
+func (e *Expression) Next(after time.Time) time.Time {
+	a := after.In(e.location).Truncate(time.Second).Add(time.Second)
+	for i := 0; i < maxSegments; i++ {
+		_, offset := a.Zone()
+		_, end := a.ZoneBounds()
+		w, ok := e.nextWall(wallClock(a))
+		if !ok {
+			return time.Time{}
+		}
+		t := e.fromWallClock(w, offset)
+		if end.IsZero() || t.Before(end) {
+			if repeated, resume := repeatedWallClock(t); repeated && !e.hourStar {
+				a = resume
+				continue
+			}
+			return t
+		}
+		// The match lies beyond this DST period. If the clocks jump forward over it,
+		// fire at the jump; otherwise continue in the next period.
+		_, nextOffset := end.Zone()
+		if nextOffset > offset && w.Before(wallClock(end)) {
+			return end
+		}
+		a = end
+	}
+	return time.Time{}
+}
+
+// Prev returns the last time strictly before before at which the expression fired,
+// in the expression's location. DST changes are handled as in Next. It returns the
+// zero time when no earlier match exists.
+func (e *Expression) Prev(before time.Time) time.Time {
+	b := before.In(e.location).Truncate(time.Second)
+	if !b.Before(before) {
+		b = b.Add(-time.Second)
+	}
+	for i := 0; i < maxSegments; i++ {
+		_, offset := b.Zone()
+		start, _ := b.ZoneBounds()
+		w, ok := e.prevWall(wallClock(b))
+		if !ok {
+			return time.Time{}
+		}
+		t := e.fromWallClock(w, offset)
+		if start.IsZero() || !t.Before(start) {
+			if repeated, _ := repeatedWallClock(t); repeated && !e.hourStar {
+				// The job ran at the first occurrence, in the previous period.
+				b = start.Add(-time.Second)
+				continue
+			}
+			return t
+		}
+		_, previousOffset := start.Add(-time.Second).Zone()
+		if offset > previousOffset && !w.Before(wallClock(start).Add(time.Duration(previousOffset-offset)*time.Second)) {
+			// The match was skipped by the clocks jumping forward and fired at the jump.
+			return start
+		}
+		b = start.Add(-time.Second)
+	}
+	return time.Time{}
+}
+
+// wallClock returns t's local date and time as a UTC time, so that arithmetic on it
+// is free of DST effects.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// fromWallClock converts a wall clock time back to an instant using a fixed offset.
+func (e *Expression) fromWallClock(w time.Time, offset int) time.Time {
+	return time.Unix(w.Unix()-int64(offset), 0).In(e.location)
+}
+
+// repeatedWallClock reports whether t's wall clock time already occurred before
+// the clocks went back, and the instant at which the repeated range ends.
+func repeatedWallClock(t time.Time) (bool, time.Time) {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false, time.Time{}
+	}
+	_, offset := t.Zone()
+	_, previousOffset := start.Add(-time.Second).Zone()
+	if previousOffset <= offset {
+		return false, time.Time{}
+	}
+	resume := start.Add(time.Duration(previousOffset-offset) * time.Second)
+	return t.Before(resume), resume
+}
+
+func (e *Expression) matchDay(day time.Time) bool {
+	if !e.month.has(int(day.Month())) {
+		return false
+	}
+	dayOfMonth := e.dayOfMonth.has(day.Day())
+	dayOfWeek := e.dayOfWeek.has(int(day.Weekday()))
+	if e.dayOfMonthStar || e.dayOfWeekStar {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+// nextWall returns the first wall clock time at or after from that matches.
+func (e *Expression) nextWall(from time.Time) (time.Time, bool) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	h, m, s := from.Hour(), from.Minute(), from.Second()
+	for i := 0; i < searchDays; i++ {
+		if e.matchDay(day) {
+			if hh, mm, ss, ok := e.nextClock(h, m, s); ok {
+				return day.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute + time.Duration(ss)*time.Second), true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+		h, m, s = 0, 0, 0
+	}
+	return time.Time{}, false
+}
+
+// prevWall returns the last wall clock time at or before from that matches.
+func (e *Expression) prevWall(from time.Time) (time.Time, bool) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	h, m, s := from.Hour(), from.Minute(), from.Second()
+	for i := 0; i < searchDays; i++ {
+		if e.matchDay(day) {
+			if hh, mm, ss, ok := e.prevClock(h, m, s); ok {
+				return day.Add(time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute + time.Duration(ss)*time.Second), true
+			}
+		}
+		day = day.AddDate(0, 0, -1)
+		h, m, s = 23, 59, 59
+	}
+	return time.Time{}, false
+}
+
+func (e *Expression) nextClock(h, m, s int) (int, int, int, bool) {
+	for ; h < 24; h, m, s = h+1, 0, 0 {
+		if !e.hour.has(h) {
+			continue
+		}
+		for ; m < 60; m, s = m+1, 0 {
+			if !e.minute.has(m) {
+				continue
+			}
+			for ; s < 60; s++ {
+				if e.second.has(s) {
+					return h, m, s, true
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
+
+func (e *Expression) prevClock(h, m, s int) (int, int, int, bool) {
+	for ; h >= 0; h, m, s = h-1, 59, 59 {
+		if !e.hour.has(h) {
+			continue
+		}
+		for ; m >= 0; m, s = m-1, 59 {
+			if !e.minute.has(m) {
+				continue
+			}
+			for ; s >= 0; s-- {
+				if e.second.has(s) {
+					return h, m, s, true
+				}
+			}
+		}
+	}
+	return 0, 0, 0, false
+}
@@ -0,0 +1,157 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %q: %v", name, err)
+	}
+	return loc
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		after    time.Time
+		expected time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC), time.Date(2026, 1, 1, 10, 1, 0, 0, time.UTC)},
+		{"strictly after", "0 10 * * *", time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)},
+		{"sub-second after", "* * * * * *", time.Date(2026, 1, 1, 10, 0, 0, 500, time.UTC), time.Date(2026, 1, 1, 10, 0, 1, 0, time.UTC)},
+		{"every 15 minutes in office hours", "*/15 9-17 * * mon-fri", time.Date(2026, 1, 2, 17, 50, 0, 0, time.UTC), time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)},
+		{"seconds field", "30 0 3 * * *", time.Date(2026, 1, 1, 3, 0, 30, 0, time.UTC), time.Date(2026, 1, 2, 3, 0, 30, 0, time.UTC)},
+		{"monthly macro", "@monthly", time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly macro", "@weekly", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"sunday alias", "0 0 * * 7", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 13 * fri", time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 13, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"leap day skipping 2100", "0 0 29 2 *", time.Date(2096, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2104, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MustParse(tt.spec).Next(tt.after)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Next(%q, %v) = %v, want %v", tt.spec, tt.after, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNext_Never(t *testing.T) {
+	if result := MustParse("0 0 30 2 *").Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !result.IsZero() {
+		t.Errorf("Next for February 30th = %v, want zero time", result)
+	}
+	if result := MustParse("0 0 30 2 *").Prev(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); !result.IsZero() {
+		t.Errorf("Prev for February 30th = %v, want zero time", result)
+	}
+}
+
+func TestNext_Timezone(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	expr, err := ParseInLocation("0 3 * * *", "Europe/Brussels")
+	if err != nil {
+		t.Fatalf("ParseInLocation returned error: %v", err)
+	}
+	result := expr.Next(time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC))
+	expected := time.Date(2026, 7, 2, 3, 0, 0, 0, brussels)
+	if !result.Equal(expected) || result.Location().String() != "Europe/Brussels" {
+		t.Errorf("Next = %v, want %v", result, expected)
+	}
+
+	prefixed := MustParse("CRON_TZ=Europe/Brussels 0 3 * * *")
+	if prefixed.Location().String() != "Europe/Brussels" {
+		t.Errorf("Location() = %v, want Europe/Brussels", prefixed.Location())
+	}
+}
+
+func TestNext_DST(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// 2026-03-29 02:00 CET jumps to 03:00 CEST, 2026-10-25 03:00 CEST goes back to 02:00 CET.
+	springJump := time.Date(2026, 3, 29, 1, 0, 0, 0, time.UTC)
+	fallBack := time.Date(2026, 10, 25, 1, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		spec     string
+		after    time.Time
+		expected []time.Time
+	}{
+		{
+			"skipped time fires at the jump",
+			"30 2 * * *",
+			time.Date(2026, 3, 28, 12, 0, 0, 0, brussels),
+			[]time.Time{springJump, time.Date(2026, 3, 30, 2, 30, 0, 0, brussels)},
+		},
+		{
+			"skipped hour fires once for wildcard minutes",
+			"*/20 2 * * *",
+			time.Date(2026, 3, 29, 1, 50, 0, 0, brussels),
+			[]time.Time{springJump, time.Date(2026, 3, 30, 2, 0, 0, 0, brussels)},
+		},
+		{
+			"fixed time in repeated hour fires once",
+			"30 2 * * *",
+			time.Date(2026, 10, 25, 0, 0, 0, 0, brussels),
+			[]time.Time{fallBack.Add(-30 * time.Minute), time.Date(2026, 10, 26, 2, 30, 0, 0, brussels)},
+		},
+		{
+			"wildcard hour fires in both occurrences",
+			"30 * * * *",
+			time.Date(2026, 10, 25, 1, 45, 0, 0, brussels),
+			[]time.Time{fallBack.Add(-30 * time.Minute), fallBack.Add(30 * time.Minute), fallBack.Add(90 * time.Minute)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseInLocation(tt.spec, "Europe/Brussels")
+			if err != nil {
+				t.Fatalf("ParseInLocation(%q) returned error: %v", tt.spec, err)
+			}
+			at := tt.after
+			for i, expected := range tt.expected {
+				next := expr.Next(at)
+				if !next.Equal(expected) {
+					t.Fatalf("run %d: Next(%v) = %v, want %v", i, at, next, expected.In(brussels))
+				}
+				// Prev must walk the same runs backwards.
+				if i > 0 {
+					if prev := expr.Prev(next); !prev.Equal(tt.expected[i-1]) {
+						t.Errorf("run %d: Prev(%v) = %v, want %v", i, next, prev, tt.expected[i-1].In(brussels))
+					}
+				}
+				at = next
+			}
+		})
+	}
+}
+
+func TestPrev(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		before   time.Time
+		expected time.Time
+	}{
+		{"strictly before", "0 10 * * *", time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"sub-second before", "* * * * * *", time.Date(2026, 1, 1, 10, 0, 1, 500, time.UTC), time.Date(2026, 1, 1, 10, 0, 1, 0, time.UTC)},
+		{"previous month", "@monthly", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"office hours", "*/15 9-17 * * mon-fri", time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC), time.Date(2026, 1, 2, 17, 45, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MustParse(tt.spec).Prev(tt.before)
+			if !result.Equal(tt.expected) {
+				t.Errorf("Prev(%q, %v) = %v, want %v", tt.spec, tt.before, result, tt.expected)
+			}
+		})
+	}
+}
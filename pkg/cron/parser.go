@@ -0,0 +1,263 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError reports which field of a cron expression could not be parsed.
+type FieldError struct {
+	// Field is the name of the failing field, e.g. "minute" or "day of week".
+	Field string
+	// Position is the zero-based index of the field in the expression.
+	Position int
+	// Value is the text of the failing field.
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("cron: invalid %s field %q (position %d): %s", e.Field, e.Value, e.Position, e.Reason)
+}
+
+type field struct {
+	name  string
+	min   int
+	max   int
+	names map[string]int
+}
+
+var (
+	secondField     = field{name: "second", min: 0, max: 59}
+	minuteField     = field{name: "minute", min: 0, max: 59}
+	hourField       = field{name: "hour", min: 0, max: 23}
+	dayOfMonthField = field{name: "day of month", min: 1, max: 31}
+	monthField      = field{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week accepts 7 as an alias for Sunday.
+	dayOfWeekField = field{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// bits is a set of allowed values for a field.
+type bits uint64
+
+func (b bits) has(value int) bool {
+	return b&(1<<uint(value)) != 0
+}
+
+// Expression is a parsed cron expression bound to a location.
+type Expression struct {
+	spec       string
+	location   *time.Location
+	second     bits
+	minute     bits
+	hour       bits
+	dayOfMonth bits
+	month      bits
+	dayOfWeek  bits
+	// Restricted day fields are combined with OR, as in Vixie cron.
+	dayOfMonthStar bool
+	dayOfWeekStar  bool
+	// Jobs with a wildcard hour run in both occurrences of a repeated DST hour.
+	hourStar bool
+}
+
+// Parse parses a cron expression evaluated in UTC. See ParseInLocation.
+func Parse(spec string) (*Expression, error) {
+	return ParseInLocation(spec, "UTC")
+}
+
+// ParseInLocation parses a cron expression evaluated in timezone. It accepts the
+// standard five fields (minute hour day-of-month month day-of-week), six fields
+// with a leading seconds field, and the macros @yearly, @annually, @monthly,
+// @weekly, @daily, @midnight and @hourly. A "CRON_TZ=" or "TZ=" prefix overrides
+// timezone.
+func ParseInLocation(spec string, timezone string) (*Expression, error) {
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		prefix, rest, _ := strings.Cut(expr, " ")
+		_, timezone, _ = strings.Cut(prefix, "=")
+		expr = strings.TrimSpace(rest)
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("cron: invalid timezone %q: %v", timezone, err)
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := macros[strings.ToLower(expr)]
+		if !ok {
+			return nil, fmt.Errorf("cron: unknown macro %q", expr)
+		}
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+	// Positions in errors refer to the fields as written, so the minute of a five
+	// field expression is reported at position 0.
+	offset := 0
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+		offset = -1
+	case 6:
+	default:
+		return nil, fmt.Errorf("cron: expected 5 or 6 fields, got %d in %q", len(fields), spec)
+	}
+	specs := []field{secondField, minuteField, hourField, dayOfMonthField, monthField, dayOfWeekField}
+
+	parsed := make([]bits, len(fields))
+	for i, f := range specs {
+		b, err := f.parse(fields[i])
+		if err != nil {
+			err.Position = i + offset
+			return nil, err
+		}
+		parsed[i] = b
+	}
+
+	// Fold the Sunday alias 7 into 0.
+	dayOfWeek := parsed[5]
+	if dayOfWeek.has(7) {
+		dayOfWeek = (dayOfWeek | 1) &^ (1 << 7)
+	}
+
+	return &Expression{
+		spec:           spec,
+		location:       location,
+		second:         parsed[0],
+		minute:         parsed[1],
+		hour:           parsed[2],
+		dayOfMonth:     parsed[3],
+		month:          parsed[4],
+		dayOfWeek:      dayOfWeek,
+		dayOfMonthStar: isWildcard(fields[3]),
+		dayOfWeekStar:  isWildcard(fields[5]),
+		hourStar:       strings.HasPrefix(fields[2], "*"),
+	}, nil
+}
+
+// MustParse is like Parse but panics when the expression is invalid.
+func MustParse(spec string) *Expression {
+	expr, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// String returns the expression as it was given to Parse.
+func (e *Expression) String() string {
+	return e.spec
+}
+
+// Location returns the location the expression is evaluated in.
+func (e *Expression) Location() *time.Location {
+	return e.location
+}
+
+func isWildcard(value string) bool {
+	return value == "*" || value == "?"
+}
+
+// parse parses a comma separated list of values, ranges and steps.
+func (f field) parse(value string) (bits, *FieldError) {
+	fail := func(format string, args ...any) (bits, *FieldError) {
+		return 0, &FieldError{Field: f.name, Value: value, Reason: fmt.Sprintf(format, args...)}
+	}
+	if value == "" {
+		return fail("empty field")
+	}
+
+	var result bits
+	for _, part := range strings.Split(value, ",") {
+		if part == "" {
+			return fail("empty list element")
+		}
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return fail("step %q must be a positive integer", stepPart)
+			}
+			step = n
+		}
+
+		var low, high int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			if rangePart == "?" && f.name != dayOfMonthField.name && f.name != dayOfWeekField.name {
+				return fail("? is only allowed for day of month and day of week")
+			}
+			low, high = f.min, f.last()
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			var err error
+			if low, err = f.value(lowPart); err != nil {
+				return fail("%v", err)
+			}
+			if high, err = f.value(highPart); err != nil {
+				return fail("%v", err)
+			}
+			if low > high {
+				return fail("range start %d is after end %d", low, high)
+			}
+		default:
+			var err error
+			if low, err = f.value(rangePart); err != nil {
+				return fail("%v", err)
+			}
+			high = low
+			// "5/15" means every 15 starting at 5.
+			if hasStep {
+				high = f.last()
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			result |= 1 << uint(v)
+		}
+	}
+	return result, nil
+}
+
+// last returns the highest value a wildcard or open step expands to. For the day of
+// week this excludes the Sunday alias 7.
+func (f field) last() int {
+	if f.name == dayOfWeekField.name {
+		return 6
+	}
+	return f.max
+}
+
+// value parses a single number or name and checks its bounds.
+func (f field) value(text string) (int, error) {
+	if n, ok := f.names[strings.ToLower(text)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", text)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", n, f.min, f.max)
+	}
+	return n, nil
+}
@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"five fields", "*/15 9-17 * * mon-fri"},
+		{"six fields", "30 0 3 * * *"},
+		{"lists and steps", "0 0,12 1-10/3 jan,jul *"},
+		{"question mark", "0 0 ? * 1"},
+		{"sunday alias", "0 0 * * 7"},
+		{"daily macro", "@daily"},
+		{"hourly macro upper case", "@HOURLY"},
+		{"timezone prefix", "CRON_TZ=Europe/Brussels 0 3 * * *"},
+		{"surrounding whitespace", "  0 3 * * *  "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.input, err)
+			}
+			if expr.String() != tt.input {
+				t.Errorf("Parse(%q).String() = %q, want %q", tt.input, expr.String(), tt.input)
+			}
+		})
+	}
+}
+
+func TestParse_FieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		field    string
+		position int
+	}{
+		{"minute out of range", "60 * * * *", "minute", 0},
+		{"hour out of range", "0 24 * * *", "hour", 1},
+		{"day of month zero", "0 0 0 * *", "day of month", 2},
+		{"unknown month name", "0 0 1 foo *", "month", 3},
+		{"day of week out of range", "0 0 * * 8", "day of week", 4},
+		{"second in six fields", "61 0 0 * * *", "second", 0},
+		{"inverted range", "0 17-9 * * *", "hour", 1},
+		{"zero step", "*/0 * * * *", "minute", 0},
+		{"empty list element", "0,,5 * * * *", "minute", 0},
+		{"question mark in hour", "0 ? * * *", "hour", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) {
+				t.Fatalf("Parse(%q) error = %v, want *FieldError", tt.input, err)
+			}
+			if fieldErr.Field != tt.field || fieldErr.Position != tt.position {
+				t.Errorf("Parse(%q) failed on %s at %d, want %s at %d", tt.input, fieldErr.Field, fieldErr.Position, tt.field, tt.position)
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Errorf("Parse(%q) error %q does not name the field", tt.input, err.Error())
+			}
+		})
+	}
+}
+
+func TestParse_OtherErrors(t *testing.T) {
+	tests := []string{"", "* * * *", "* * * * * * *", "@fortnightly", "TZ=Mars/Olympus 0 0 * * *"}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := Parse(input); err == nil {
+				t.Errorf("Parse(%q) expected error but got none", input)
+			}
+		})
+	}
+
+	if _, err := ParseInLocation("0 0 * * *", "Mars/Olympus"); err == nil {
+		t.Errorf("ParseInLocation with invalid timezone expected error but got none")
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustParse with invalid expression did not panic")
+		}
+	}()
+	MustParse("invalid")
+}
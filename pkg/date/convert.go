@@ -125,3 +125,13 @@ func FormatDurationShortMillis(durationMs int) string {
 	}
 	return strings.Join(parts, " ")
 }
+
+// loadLocation returns the location for the given timezone name, falling back to UTC
+// when the name is empty or unknown.
+func loadLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || loc == nil {
+		return time.UTC
+	}
+	return loc
+}
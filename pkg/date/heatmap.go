@@ -0,0 +1,149 @@
+package date
+
+import (
+	"time"
+)
+
+// DSTTransition describes a clock change found while building a Heatmap.
+type DSTTransition struct {
+	// Timestamp is the first second using the new offset.
+	Timestamp int64 `json:"timestamp" bson:"timestamp"`
+	// Weekday and Hour identify the local hour that was skipped or repeated.
+	Weekday time.Weekday `json:"weekday" bson:"weekday"`
+	Hour    int          `json:"hour" bson:"hour"`
+	// Shift is the offset change in seconds: positive when clocks go forward (a gap),
+	// negative when they go back (an overlap).
+	Shift int `json:"shift" bson:"shift"`
+}
+
+// IsGap reports whether the transition skipped a local hour.
+func (t DSTTransition) IsGap() bool {
+	return t.Shift > 0
+}
+
+// Heatmap is an hour-by-weekday activity matrix, indexed as [time.Weekday][hour].
+//
+// Counts alone are skewed on DST days: a repeated hour collects two hours of events
+// and a skipped hour collects none. Exposure records how many real hours each cell
+// was observed for, so PerDay divides by actual observation time instead of the
+// number of days.
+type Heatmap struct {
+	Timezone    string          `json:"timezone" bson:"timezone"`
+	Counts      [7][24]int      `json:"counts" bson:"counts"`
+	Exposure    [7][24]float64  `json:"exposure" bson:"exposure"`
+	Days        [7]int          `json:"days" bson:"days"`
+	Transitions []DSTTransition `json:"transitions,omitempty" bson:"transitions,omitempty"`
+}
+
+// BuildHeatmap counts timestamps per local weekday and hour in timezone over the
+// observed range. When observed is empty, the local days spanned by the timestamps
+// are used. Timestamps outside the observed range are ignored.
+func BuildHeatmap(timezone string, observed TimeRange, timestamps []int64) Heatmap {
+	loc := loadLocation(timezone)
+	heatmap := Heatmap{Timezone: loc.String()}
+
+	if observed.IsEmpty() {
+		if len(timestamps) == 0 {
+			return heatmap
+		}
+		first, last := timestamps[0], timestamps[0]
+		for _, ts := range timestamps[1:] {
+			first = min(first, ts)
+			last = max(last, ts)
+		}
+		observed = TimeRange{Start: StartOfDay(timezone, first), End: EndOfDay(timezone, last)}
+	}
+
+	for _, day := range observed.Split(timezone, SplitByDay) {
+		heatmap.Days[time.Unix(day.Start, 0).In(loc).Weekday()]++
+	}
+
+	var previous time.Time
+	for i, hour := range observed.Split(timezone, SplitByHour) {
+		local := time.Unix(hour.Start, 0).In(loc)
+		heatmap.Exposure[local.Weekday()][local.Hour()] += float64(hour.Duration()) / 3600
+
+		if i > 0 {
+			_, before := previous.Zone()
+			_, after := local.Zone()
+			if before != after {
+				transition := DSTTransition{
+					Timestamp: hour.Start,
+					Weekday:   local.Weekday(),
+					Hour:      local.Hour(),
+					Shift:     after - before,
+				}
+				if transition.IsGap() {
+					transition.Hour = (local.Hour() + 23) % 24
+				}
+				heatmap.Transitions = append(heatmap.Transitions, transition)
+			}
+		}
+		previous = local
+	}
+
+	for _, ts := range timestamps {
+		if !observed.Contains(ts) {
+			continue
+		}
+		local := time.Unix(ts, 0).In(loc)
+		heatmap.Counts[local.Weekday()][local.Hour()]++
+	}
+	return heatmap
+}
+
+// Total returns the number of counted events.
+func (h Heatmap) Total() int {
+	total := 0
+	for _, hours := range h.Counts {
+		for _, count := range hours {
+			total += count
+		}
+	}
+	return total
+}
+
+// HourTotals collapses the weekdays into a 24 bucket histogram.
+func (h Heatmap) HourTotals() [24]int {
+	var totals [24]int
+	for _, hours := range h.Counts {
+		for hour, count := range hours {
+			totals[hour] += count
+		}
+	}
+	return totals
+}
+
+// PerDay returns the average number of events per observed hour for each cell, i.e.
+// the count per day for a regular hour. Cells that were never observed are 0.
+func (h Heatmap) PerDay() [7][24]float64 {
+	var rates [7][24]float64
+	for weekday := range h.Counts {
+		for hour, count := range h.Counts[weekday] {
+			if exposure := h.Exposure[weekday][hour]; exposure > 0 {
+				rates[weekday][hour] = float64(count) / exposure
+			}
+		}
+	}
+	return rates
+}
+
+// Normalized returns PerDay scaled so that the busiest cell is 1.
+func (h Heatmap) Normalized() [7][24]float64 {
+	rates := h.PerDay()
+	peak := 0.0
+	for _, hours := range rates {
+		for _, rate := range hours {
+			peak = max(peak, rate)
+		}
+	}
+	if peak == 0 {
+		return rates
+	}
+	for weekday := range rates {
+		for hour := range rates[weekday] {
+			rates[weekday][hour] /= peak
+		}
+	}
+	return rates
+}
@@ -0,0 +1,166 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildHeatmap_RegularWeek(t *testing.T) {
+	// Monday 2026-07-13 until Monday 2026-07-20 in UTC, one event every day at 09:30.
+	observed := TimeRange{
+		Start: time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC).Unix(),
+		End:   time.Date(2026, 7, 20, 0, 0, 0, 0, time.UTC).Unix(),
+	}
+	var timestamps []int64
+	for day := 13; day < 20; day++ {
+		timestamps = append(timestamps, time.Date(2026, 7, day, 9, 30, 0, 0, time.UTC).Unix())
+	}
+	timestamps = append(timestamps, observed.End+10) // outside the observed range
+
+	heatmap := BuildHeatmap("UTC", observed, timestamps)
+	if heatmap.Total() != 7 {
+		t.Errorf("Total() = %d, want 7", heatmap.Total())
+	}
+	for weekday := 0; weekday < 7; weekday++ {
+		if heatmap.Days[weekday] != 1 {
+			t.Errorf("Days[%d] = %d, want 1", weekday, heatmap.Days[weekday])
+		}
+		if heatmap.Counts[weekday][9] != 1 {
+			t.Errorf("Counts[%d][9] = %d, want 1", weekday, heatmap.Counts[weekday][9])
+		}
+		if heatmap.Exposure[weekday][9] != 1 {
+			t.Errorf("Exposure[%d][9] = %f, want 1", weekday, heatmap.Exposure[weekday][9])
+		}
+	}
+	if totals := heatmap.HourTotals(); totals[9] != 7 {
+		t.Errorf("HourTotals()[9] = %d, want 7", totals[9])
+	}
+	if len(heatmap.Transitions) != 0 {
+		t.Errorf("Transitions = %v, want none", heatmap.Transitions)
+	}
+}
+
+func TestBuildHeatmap_FallBackOverlap(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// Sunday 2026-10-25: 02:00-03:00 local happens twice. One event per real hour.
+	day := DayRange("Europe/Brussels", time.Date(2026, 10, 25, 12, 0, 0, 0, brussels).Unix())
+	var timestamps []int64
+	for ts := day.Start; ts < day.End; ts += 3600 {
+		timestamps = append(timestamps, ts+60)
+	}
+
+	heatmap := BuildHeatmap("Europe/Brussels", day, timestamps)
+	sunday := time.Sunday
+	if heatmap.Counts[sunday][2] != 2 {
+		t.Errorf("Counts[Sunday][2] = %d, want 2", heatmap.Counts[sunday][2])
+	}
+	if heatmap.Exposure[sunday][2] != 2 {
+		t.Errorf("Exposure[Sunday][2] = %f, want 2", heatmap.Exposure[sunday][2])
+	}
+	perDay := heatmap.PerDay()
+	for hour := 0; hour < 24; hour++ {
+		if perDay[sunday][hour] != 1 {
+			t.Errorf("PerDay()[Sunday][%d] = %f, want 1", hour, perDay[sunday][hour])
+		}
+	}
+
+	if len(heatmap.Transitions) != 1 {
+		t.Fatalf("Transitions = %v, want exactly one", heatmap.Transitions)
+	}
+	transition := heatmap.Transitions[0]
+	if transition.IsGap() || transition.Hour != 2 || transition.Weekday != sunday || transition.Shift != -3600 {
+		t.Errorf("Transition = %+v, want overlap at Sunday 02:00 with shift -3600", transition)
+	}
+}
+
+func TestBuildHeatmap_SpringForwardGap(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// Sunday 2026-03-29: 02:00-03:00 local does not exist.
+	day := DayRange("Europe/Brussels", time.Date(2026, 3, 29, 12, 0, 0, 0, brussels).Unix())
+	var timestamps []int64
+	for ts := day.Start; ts < day.End; ts += 3600 {
+		timestamps = append(timestamps, ts+60)
+	}
+
+	heatmap := BuildHeatmap("Europe/Brussels", day, timestamps)
+	sunday := time.Sunday
+	if heatmap.Counts[sunday][2] != 0 || heatmap.Exposure[sunday][2] != 0 {
+		t.Errorf("skipped hour has Counts=%d Exposure=%f, want 0 and 0", heatmap.Counts[sunday][2], heatmap.Exposure[sunday][2])
+	}
+	if perDay := heatmap.PerDay(); perDay[sunday][2] != 0 || perDay[sunday][3] != 1 {
+		t.Errorf("PerDay()[Sunday] = %v, want 0 at hour 2 and 1 at hour 3", perDay[sunday])
+	}
+	if len(heatmap.Transitions) != 1 || !heatmap.Transitions[0].IsGap() || heatmap.Transitions[0].Hour != 2 {
+		t.Errorf("Transitions = %+v, want one gap at hour 2", heatmap.Transitions)
+	}
+}
+
+func TestBuildHeatmap_MidnightSpringForward(t *testing.T) {
+	// On these Sundays clocks jump from 00:00 to 01:00, so the day starts at 01:00.
+	// time.Date normalizes the missing midnight backwards in Santiago and forwards
+	// in Beirut.
+	tests := []struct {
+		timezone string
+		saturday time.Time
+	}{
+		{"America/Santiago", time.Date(2022, 9, 10, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Beirut", time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			loc := mustLoadLocation(t, tt.timezone)
+			year, month, day := tt.saturday.Date()
+			timestamps := []int64{
+				time.Date(year, month, day, 12, 0, 0, 0, loc).Unix(),
+				time.Date(year, month, day+1, 1, 30, 0, 0, loc).Unix(),
+			}
+
+			heatmap := BuildHeatmap(tt.timezone, TimeRange{}, timestamps)
+			if heatmap.Days[time.Saturday] != 1 || heatmap.Days[time.Sunday] != 1 {
+				t.Errorf("Days = %v, want one Saturday and one Sunday", heatmap.Days)
+			}
+			if heatmap.Exposure[time.Saturday][23] != 1 || heatmap.Exposure[time.Sunday][0] != 0 {
+				t.Errorf("Exposure Saturday 23:00 = %f, Sunday 00:00 = %f, want 1 and 0",
+					heatmap.Exposure[time.Saturday][23], heatmap.Exposure[time.Sunday][0])
+			}
+			if heatmap.Counts[time.Sunday][1] != 1 {
+				t.Errorf("Counts[Sunday][1] = %d, want 1", heatmap.Counts[time.Sunday][1])
+			}
+			if len(heatmap.Transitions) != 1 || !heatmap.Transitions[0].IsGap() ||
+				heatmap.Transitions[0].Weekday != time.Sunday || heatmap.Transitions[0].Hour != 0 {
+				t.Errorf("Transitions = %+v, want one gap at Sunday 00:00", heatmap.Transitions)
+			}
+		})
+	}
+}
+
+func TestBuildHeatmap_DerivedRangeAndNormalization(t *testing.T) {
+	// Two Mondays with three events at 10:00 in total, one Tuesday event at 11:00.
+	timestamps := []int64{
+		time.Date(2026, 7, 13, 10, 5, 0, 0, time.UTC).Unix(),
+		time.Date(2026, 7, 13, 10, 10, 0, 0, time.UTC).Unix(),
+		time.Date(2026, 7, 14, 11, 0, 0, 0, time.UTC).Unix(),
+		time.Date(2026, 7, 20, 10, 0, 0, 0, time.UTC).Unix(),
+	}
+
+	heatmap := BuildHeatmap("UTC", TimeRange{}, timestamps)
+	if heatmap.Days[time.Monday] != 2 || heatmap.Days[time.Tuesday] != 1 {
+		t.Errorf("Days = %v, want 2 Mondays and 1 Tuesday", heatmap.Days)
+	}
+	perDay := heatmap.PerDay()
+	if perDay[time.Monday][10] != 1.5 {
+		t.Errorf("PerDay()[Monday][10] = %f, want 1.5", perDay[time.Monday][10])
+	}
+	normalized := heatmap.Normalized()
+	if normalized[time.Monday][10] != 1 {
+		t.Errorf("Normalized()[Monday][10] = %f, want 1", normalized[time.Monday][10])
+	}
+	if normalized[time.Tuesday][11] != 1/1.5 {
+		t.Errorf("Normalized()[Tuesday][11] = %f, want %f", normalized[time.Tuesday][11], 1/1.5)
+	}
+
+	empty := BuildHeatmap("UTC", TimeRange{}, nil)
+	if empty.Total() != 0 || empty.Normalized() != ([7][24]float64{}) {
+		t.Errorf("empty heatmap = %+v, want zero values", empty)
+	}
+}
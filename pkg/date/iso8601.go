@@ -0,0 +1,408 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// ISODuration is an ISO 8601 duration such as "P1Y2M3DT4H5M6.5S" or "P2W".
+// Years, months, weeks and days are calendar units and are applied with
+// time.AddDate, so adding "P1M" to January 31st follows Go's normalization.
+type ISODuration struct {
+	Negative bool
+	Years    int
+	Months   int
+	Weeks    int
+	Days     int
+	Hours    int
+	Minutes  int
+	Seconds  float64
+}
+
+// ParseISODuration parses an ISO 8601 duration. A leading "-" marks a negative
+// duration and a fractional value is only accepted for the seconds component.
+func ParseISODuration(value string) (ISODuration, error) {
+	var d ISODuration
+	s := value
+	if strings.HasPrefix(s, "-") {
+		d.Negative = true
+		s = s[1:]
+	} else if strings.HasPrefix(s, "+") {
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") {
+		return ISODuration{}, fmt.Errorf("invalid ISO 8601 duration %q: missing leading P", value)
+	}
+	s = s[1:]
+	if s == "" {
+		return ISODuration{}, fmt.Errorf("invalid ISO 8601 duration %q: no components", value)
+	}
+
+	datePart, timePart, hasTime := strings.Cut(s, "T")
+	if hasTime && timePart == "" {
+		return ISODuration{}, fmt.Errorf("invalid ISO 8601 duration %q: empty time part", value)
+	}
+
+	// Designators must appear in this order and at most once.
+	dateUnits := []byte{'Y', 'M', 'W', 'D'}
+	timeUnits := []byte{'H', 'M', 'S'}
+
+	parsePart := func(part string, units []byte, assign func(unit byte, number string) error) error {
+		next := 0
+		for part != "" {
+			i := 0
+			for i < len(part) && (part[i] >= '0' && part[i] <= '9' || part[i] == '.' || part[i] == ',') {
+				i++
+			}
+			if i == 0 || i == len(part) {
+				return fmt.Errorf("invalid ISO 8601 duration %q: malformed component %q", value, part)
+			}
+			unit := part[i]
+			pos := -1
+			for j := next; j < len(units); j++ {
+				if units[j] == unit {
+					pos = j
+					break
+				}
+			}
+			if pos < 0 {
+				return fmt.Errorf("invalid ISO 8601 duration %q: unexpected designator %q", value, unit)
+			}
+			next = pos + 1
+			if err := assign(unit, part[:i]); err != nil {
+				return err
+			}
+			part = part[i+1:]
+		}
+		return nil
+	}
+
+	parseInt := func(number string) (int, error) {
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			return 0, fmt.Errorf("invalid ISO 8601 duration %q: %q is not an integer", value, number)
+		}
+		return n, nil
+	}
+
+	err := parsePart(datePart, dateUnits, func(unit byte, number string) error {
+		n, err := parseInt(number)
+		if err != nil {
+			return err
+		}
+		switch unit {
+		case 'Y':
+			d.Years = n
+		case 'M':
+			d.Months = n
+		case 'W':
+			d.Weeks = n
+		case 'D':
+			d.Days = n
+		}
+		return nil
+	})
+	if err != nil {
+		return ISODuration{}, err
+	}
+
+	err = parsePart(timePart, timeUnits, func(unit byte, number string) error {
+		if unit == 'S' {
+			f, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+			if err != nil {
+				return fmt.Errorf("invalid ISO 8601 duration %q: %q is not a number", value, number)
+			}
+			d.Seconds = f
+			return nil
+		}
+		n, err := parseInt(number)
+		if err != nil {
+			return err
+		}
+		if unit == 'H' {
+			d.Hours = n
+		} else {
+			d.Minutes = n
+		}
+		return nil
+	})
+	if err != nil {
+		return ISODuration{}, err
+	}
+	return d, nil
+}
+
+// ISODurationFromDuration converts a fixed time.Duration into hours, minutes
+// and seconds. Hours are never folded into days, as a day is not always 24h.
+func ISODurationFromDuration(duration time.Duration) ISODuration {
+	var d ISODuration
+	if duration < 0 {
+		d.Negative = true
+		duration = -duration
+	}
+	d.Hours = int(duration / time.Hour)
+	duration -= time.Duration(d.Hours) * time.Hour
+	d.Minutes = int(duration / time.Minute)
+	duration -= time.Duration(d.Minutes) * time.Minute
+	d.Seconds = duration.Seconds()
+	return d
+}
+
+// IsZero reports whether all components of the duration are zero.
+func (d ISODuration) IsZero() bool {
+	return d.Years == 0 && d.Months == 0 && d.Weeks == 0 && d.Days == 0 &&
+		d.Hours == 0 && d.Minutes == 0 && d.Seconds == 0
+}
+
+// Negate returns the duration with its sign flipped.
+func (d ISODuration) Negate() ISODuration {
+	d.Negative = !d.Negative
+	return d
+}
+
+// String formats the duration in ISO 8601 notation, e.g. "P1DT2H".
+func (d ISODuration) String() string {
+	if d.IsZero() {
+		return "PT0S"
+	}
+	var b strings.Builder
+	if d.Negative {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	writeUnit := func(n int, unit byte) {
+		if n != 0 {
+			b.WriteString(strconv.Itoa(n))
+			b.WriteByte(unit)
+		}
+	}
+	writeUnit(d.Years, 'Y')
+	writeUnit(d.Months, 'M')
+	writeUnit(d.Weeks, 'W')
+	writeUnit(d.Days, 'D')
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 {
+		b.WriteByte('T')
+		writeUnit(d.Hours, 'H')
+		writeUnit(d.Minutes, 'M')
+		if d.Seconds != 0 {
+			b.WriteString(strconv.FormatFloat(d.Seconds, 'f', -1, 64))
+			b.WriteByte('S')
+		}
+	}
+	return b.String()
+}
+
+// ToDuration converts the duration to a time.Duration, counting a day as 24h
+// and a week as 7 days. It fails when years or months are set, since their
+// length depends on the date they are applied to; use AddTo instead.
+func (d ISODuration) ToDuration() (time.Duration, error) {
+	if d.Years != 0 || d.Months != 0 {
+		return 0, fmt.Errorf("ISO 8601 duration %s has calendar components and no fixed length", d)
+	}
+	total := time.Duration(d.Weeks*7+d.Days)*24*time.Hour +
+		time.Duration(d.Hours)*time.Hour +
+		time.Duration(d.Minutes)*time.Minute +
+		time.Duration(math.Round(d.Seconds*float64(time.Second)))
+	if d.Negative {
+		total = -total
+	}
+	return total, nil
+}
+
+// AddTo adds the duration to t. Calendar components are applied first in t's
+// location, so "P1D" keeps the wall clock time across a DST change.
+func (d ISODuration) AddTo(t time.Time) time.Time {
+	sign := 1
+	if d.Negative {
+		sign = -1
+	}
+	t = t.AddDate(sign*d.Years, sign*d.Months, sign*(d.Weeks*7+d.Days))
+	clock := time.Duration(d.Hours)*time.Hour +
+		time.Duration(d.Minutes)*time.Minute +
+		time.Duration(math.Round(d.Seconds*float64(time.Second)))
+	return t.Add(time.Duration(sign) * clock)
+}
+
+// MarshalJSON encodes the duration as an ISO 8601 string.
+func (d ISODuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON decodes an ISO 8601 duration string.
+func (d *ISODuration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseISODuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalBSONValue encodes the duration as an ISO 8601 string.
+func (d ISODuration) MarshalBSONValue() (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(d.String())
+	return byte(typ), data, err
+}
+
+// UnmarshalBSONValue decodes an ISO 8601 duration string.
+func (d *ISODuration) UnmarshalBSONValue(typ byte, data []byte) error {
+	s, ok := bson.RawValue{Type: bson.Type(typ), Value: data}.StringValueOK()
+	if !ok {
+		return fmt.Errorf("cannot decode BSON %s into ISODuration", bson.Type(typ))
+	}
+	parsed, err := ParseISODuration(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Interval is an ISO 8601 time interval in one of the forms "start/end",
+// "start/duration" or "duration/end". Only the parts present in the original
+// notation are set; Bounds resolves the missing one.
+type Interval struct {
+	Start    time.Time
+	End      time.Time
+	Duration ISODuration
+}
+
+var intervalTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+func parseIntervalTime(value string) (time.Time, error) {
+	for _, layout := range intervalTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid ISO 8601 time %q", value)
+}
+
+// ParseInterval parses an ISO 8601 interval such as
+// "2026-01-01T00:00Z/P1D" or "2026-01-01T00:00:00Z/2026-01-02T00:00:00Z".
+// Times without an offset are interpreted as UTC.
+func ParseInterval(value string) (Interval, error) {
+	first, second, ok := strings.Cut(value, "/")
+	if !ok || first == "" || second == "" {
+		return Interval{}, fmt.Errorf("invalid ISO 8601 interval %q: expected two parts separated by /", value)
+	}
+
+	var interval Interval
+	firstIsDuration := strings.HasPrefix(first, "P")
+	secondIsDuration := strings.HasPrefix(second, "P")
+	if firstIsDuration && secondIsDuration {
+		return Interval{}, fmt.Errorf("invalid ISO 8601 interval %q: both parts are durations", value)
+	}
+
+	var err error
+	if firstIsDuration {
+		if interval.Duration, err = ParseISODuration(first); err != nil {
+			return Interval{}, err
+		}
+	} else if interval.Start, err = parseIntervalTime(first); err != nil {
+		return Interval{}, err
+	}
+	if secondIsDuration {
+		if interval.Duration, err = ParseISODuration(second); err != nil {
+			return Interval{}, err
+		}
+	} else if interval.End, err = parseIntervalTime(second); err != nil {
+		return Interval{}, err
+	}
+
+	start, end := interval.Bounds()
+	if end.Before(start) {
+		return Interval{}, fmt.Errorf("invalid ISO 8601 interval %q: end is before start", value)
+	}
+	return interval, nil
+}
+
+// NewInterval returns a start/end interval.
+func NewInterval(start, end time.Time) Interval {
+	return Interval{Start: start, End: end}
+}
+
+// Bounds returns the resolved start and end of the interval.
+func (i Interval) Bounds() (time.Time, time.Time) {
+	switch {
+	case i.Start.IsZero() && !i.End.IsZero():
+		return i.Duration.Negate().AddTo(i.End), i.End
+	case !i.Start.IsZero() && i.End.IsZero():
+		return i.Start, i.Duration.AddTo(i.Start)
+	default:
+		return i.Start, i.End
+	}
+}
+
+// Contains reports whether t lies within [start, end).
+func (i Interval) Contains(t time.Time) bool {
+	start, end := i.Bounds()
+	return !t.Before(start) && t.Before(end)
+}
+
+// String formats the interval in the same form it was expressed in.
+func (i Interval) String() string {
+	switch {
+	case i.Start.IsZero() && !i.End.IsZero():
+		return i.Duration.String() + "/" + i.End.Format(time.RFC3339Nano)
+	case !i.Start.IsZero() && i.End.IsZero():
+		return i.Start.Format(time.RFC3339Nano) + "/" + i.Duration.String()
+	default:
+		return i.Start.Format(time.RFC3339Nano) + "/" + i.End.Format(time.RFC3339Nano)
+	}
+}
+
+// MarshalJSON encodes the interval as an ISO 8601 string.
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String())
+}
+
+// UnmarshalJSON decodes an ISO 8601 interval string.
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseInterval(s)
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
+
+// MarshalBSONValue encodes the interval as an ISO 8601 string.
+func (i Interval) MarshalBSONValue() (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(i.String())
+	return byte(typ), data, err
+}
+
+// UnmarshalBSONValue decodes an ISO 8601 interval string.
+func (i *Interval) UnmarshalBSONValue(typ byte, data []byte) error {
+	s, ok := bson.RawValue{Type: bson.Type(typ), Value: data}.StringValueOK()
+	if !ok {
+		return fmt.Errorf("cannot decode BSON %s into Interval", bson.Type(typ))
+	}
+	parsed, err := ParseInterval(s)
+	if err != nil {
+		return err
+	}
+	*i = parsed
+	return nil
+}
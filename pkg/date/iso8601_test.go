@@ -0,0 +1,309 @@
+package date
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestParseISODuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ISODuration
+	}{
+		{"days and hours", "P1DT2H", ISODuration{Days: 1, Hours: 2}},
+		{"all components", "P1Y2M3W4DT5H6M7.5S", ISODuration{Years: 1, Months: 2, Weeks: 3, Days: 4, Hours: 5, Minutes: 6, Seconds: 7.5}},
+		{"minutes only", "PT30M", ISODuration{Minutes: 30}},
+		{"month vs minute", "P1MT1M", ISODuration{Months: 1, Minutes: 1}},
+		{"comma decimal", "PT0,5S", ISODuration{Seconds: 0.5}},
+		{"negative", "-P1D", ISODuration{Negative: true, Days: 1}},
+		{"zero", "PT0S", ISODuration{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseISODuration(tt.input)
+			if err != nil {
+				t.Fatalf("ParseISODuration(%q) returned error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseISODuration(%q) = %+v, want %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseISODuration_Invalid(t *testing.T) {
+	tests := []string{"", "1D", "P", "PT", "P1H", "PT1D", "P1D1Y", "P1.5D", "PXD", "P1", "P1DT"}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseISODuration(input); err == nil {
+				t.Errorf("ParseISODuration(%q) expected error but got none", input)
+			}
+		})
+	}
+}
+
+func TestISODurationString(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    ISODuration
+		expected string
+	}{
+		{"zero", ISODuration{}, "PT0S"},
+		{"days and hours", ISODuration{Days: 1, Hours: 2}, "P1DT2H"},
+		{"fractional seconds", ISODuration{Minutes: 1, Seconds: 1.25}, "PT1M1.25S"},
+		{"weeks", ISODuration{Weeks: 2}, "P2W"},
+		{"negative", ISODuration{Negative: true, Years: 1}, "-P1Y"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.input.String()
+			if result != tt.expected {
+				t.Errorf("%+v.String() = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestISODurationFromDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    time.Duration
+		expected string
+	}{
+		{"zero", 0, "PT0S"},
+		{"hour and a half", 90 * time.Minute, "PT1H30M"},
+		{"more than a day", 26*time.Hour + 1500*time.Millisecond, "PT26H1.5S"},
+		{"negative", -45 * time.Second, "-PT45S"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ISODurationFromDuration(tt.input).String()
+			if result != tt.expected {
+				t.Errorf("ISODurationFromDuration(%v) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestISODurationToDuration(t *testing.T) {
+	d := ISODuration{Weeks: 1, Days: 1, Hours: 1, Minutes: 1, Seconds: 1.5}
+	got, err := d.ToDuration()
+	if err != nil {
+		t.Fatalf("ToDuration() returned error: %v", err)
+	}
+	want := 8*24*time.Hour + time.Hour + time.Minute + 1500*time.Millisecond
+	if got != want {
+		t.Errorf("ToDuration() = %v, want %v", got, want)
+	}
+
+	if _, err := (ISODuration{Months: 1}).ToDuration(); err == nil {
+		t.Errorf("ToDuration() with months expected error but got none")
+	}
+}
+
+func TestISODurationAddTo(t *testing.T) {
+	brussels, err := time.LoadLocation("Europe/Brussels")
+	if err != nil {
+		t.Fatalf("failed to load location: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		start    time.Time
+		duration string
+		expected time.Time
+	}{
+		{"one month", time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC), "P1M", time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)},
+		{"leap year", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "P1Y", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"day across DST keeps wall clock", time.Date(2026, 3, 28, 12, 0, 0, 0, brussels), "P1D", time.Date(2026, 3, 29, 12, 0, 0, 0, brussels)},
+		{"24 hours across DST", time.Date(2026, 3, 28, 12, 0, 0, 0, brussels), "PT24H", time.Date(2026, 3, 29, 13, 0, 0, 0, brussels)},
+		{"negative", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), "-P1DT1H", time.Date(2025, 12, 30, 23, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseISODuration(tt.duration)
+			if err != nil {
+				t.Fatalf("ParseISODuration(%q) returned error: %v", tt.duration, err)
+			}
+			result := d.AddTo(tt.start)
+			if !result.Equal(tt.expected) {
+				t.Errorf("%s.AddTo(%v) = %v, want %v", tt.duration, tt.start, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestISODurationJSON(t *testing.T) {
+	type payload struct {
+		Retention ISODuration `json:"retention"`
+	}
+	data, err := json.Marshal(payload{Retention: ISODuration{Days: 30}})
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	if string(data) != `{"retention":"P30D"}` {
+		t.Errorf("json.Marshal = %s, want %s", data, `{"retention":"P30D"}`)
+	}
+
+	var decoded payload
+	if err := json.Unmarshal([]byte(`{"retention":"PT1H"}`), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if decoded.Retention != (ISODuration{Hours: 1}) {
+		t.Errorf("json.Unmarshal = %+v, want %+v", decoded.Retention, ISODuration{Hours: 1})
+	}
+
+	if err := json.Unmarshal([]byte(`{"retention":"1h"}`), &decoded); err == nil {
+		t.Errorf("json.Unmarshal with invalid duration expected error but got none")
+	}
+}
+
+func TestISODurationBSON(t *testing.T) {
+	type document struct {
+		Retention ISODuration `bson:"retention"`
+	}
+	data, err := bson.Marshal(document{Retention: ISODuration{Days: 1, Hours: 2}})
+	if err != nil {
+		t.Fatalf("bson.Marshal returned error: %v", err)
+	}
+	value, err := bson.Raw(data).LookupErr("retention")
+	if err != nil {
+		t.Fatalf("LookupErr returned error: %v", err)
+	}
+	if s, ok := value.StringValueOK(); !ok || s != "P1DT2H" {
+		t.Errorf("BSON retention = %v, want %q", value, "P1DT2H")
+	}
+
+	var decoded document
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("bson.Unmarshal returned error: %v", err)
+	}
+	if decoded.Retention != (ISODuration{Days: 1, Hours: 2}) {
+		t.Errorf("bson.Unmarshal = %+v, want %+v", decoded.Retention, ISODuration{Days: 1, Hours: 2})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		input    string
+		str      string
+		expected [2]time.Time
+	}{
+		{"start and duration", "2026-01-01T00:00Z/P1D", "2026-01-01T00:00:00Z/P1D", [2]time.Time{start, end}},
+		{"start and end", "2026-01-01T00:00:00Z/2026-01-02T00:00:00Z", "2026-01-01T00:00:00Z/2026-01-02T00:00:00Z", [2]time.Time{start, end}},
+		{"duration and end", "PT24H/2026-01-02T00:00:00Z", "PT24H/2026-01-02T00:00:00Z", [2]time.Time{start, end}},
+		{"offset", "2026-01-01T01:00:00+01:00/P1D", "2026-01-01T01:00:00+01:00/P1D", [2]time.Time{start, end}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interval, err := ParseInterval(tt.input)
+			if err != nil {
+				t.Fatalf("ParseInterval(%q) returned error: %v", tt.input, err)
+			}
+			s, e := interval.Bounds()
+			if !s.Equal(tt.expected[0]) || !e.Equal(tt.expected[1]) {
+				t.Errorf("ParseInterval(%q).Bounds() = %v, %v, want %v, %v", tt.input, s, e, tt.expected[0], tt.expected[1])
+			}
+			if interval.String() != tt.str {
+				t.Errorf("ParseInterval(%q).String() = %q, want %q", tt.input, interval.String(), tt.str)
+			}
+		})
+	}
+}
+
+func TestParseInterval_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"2026-01-01T00:00:00Z",
+		"P1D/P1D",
+		"2026-01-01T00:00:00Z/P1X",
+		"yesterday/P1D",
+		"2026-01-02T00:00:00Z/2026-01-01T00:00:00Z",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseInterval(input); err == nil {
+				t.Errorf("ParseInterval(%q) expected error but got none", input)
+			}
+		})
+	}
+}
+
+func TestIntervalContains(t *testing.T) {
+	interval, err := ParseInterval("2026-01-01T00:00Z/P1D")
+	if err != nil {
+		t.Fatalf("ParseInterval returned error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"at start", time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), true},
+		{"inside", time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), true},
+		{"at end", time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), false},
+		{"before", time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := interval.Contains(tt.at); result != tt.expected {
+				t.Errorf("Contains(%v) = %v, want %v", tt.at, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIntervalJSONAndBSON(t *testing.T) {
+	type payload struct {
+		Window Interval `json:"window" bson:"window"`
+	}
+	interval, err := ParseInterval("2026-01-01T00:00Z/P1D")
+	if err != nil {
+		t.Fatalf("ParseInterval returned error: %v", err)
+	}
+
+	data, err := json.Marshal(payload{Window: interval})
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+	want := `{"window":"2026-01-01T00:00:00Z/P1D"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal = %s, want %s", data, want)
+	}
+	var fromJSON payload
+	if err := json.Unmarshal(data, &fromJSON); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if fromJSON.Window.String() != interval.String() {
+		t.Errorf("json round trip = %q, want %q", fromJSON.Window.String(), interval.String())
+	}
+
+	raw, err := bson.Marshal(payload{Window: interval})
+	if err != nil {
+		t.Fatalf("bson.Marshal returned error: %v", err)
+	}
+	var fromBSON payload
+	if err := bson.Unmarshal(raw, &fromBSON); err != nil {
+		t.Fatalf("bson.Unmarshal returned error: %v", err)
+	}
+	if fromBSON.Window.String() != interval.String() {
+		t.Errorf("bson round trip = %q, want %q", fromBSON.Window.String(), interval.String())
+	}
+}
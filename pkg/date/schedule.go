@@ -0,0 +1,192 @@
+package date
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ScheduleRule activates a schedule on the given weekdays between two local wall
+// clock times in "15:04" format. When End is not after Start the span runs
+// overnight, e.g. Start "18:00" and End "07:00" on Monday covers Monday 18:00 until
+// Tuesday 07:00. An End of "24:00" means midnight at the end of the day.
+type ScheduleRule struct {
+	Weekdays []time.Weekday `json:"weekdays" bson:"weekdays"`
+	Start    string         `json:"start" bson:"start"`
+	End      string         `json:"end" bson:"end"`
+}
+
+// ScheduleException overrides all rules for a single local date in "02-01-2006"
+// format, as returned by GetDate. Active true arms the schedule for the whole day,
+// false disarms it (e.g. a public holiday).
+type ScheduleException struct {
+	Date   string `json:"date" bson:"date"`
+	Active bool   `json:"active" bson:"active"`
+	Name   string `json:"name,omitempty" bson:"name,omitempty"`
+}
+
+// Schedule is a weekly schedule evaluated in a timezone, such as the hours a camera
+// site is armed.
+type Schedule struct {
+	Timezone   string              `json:"timezone" bson:"timezone"`
+	Rules      []ScheduleRule      `json:"rules" bson:"rules"`
+	Exceptions []ScheduleException `json:"exceptions,omitempty" bson:"exceptions,omitempty"`
+}
+
+// parseClock parses a "15:04" wall clock time into seconds since midnight.
+func parseClock(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 3600, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*3600 + t.Minute()*60, nil
+}
+
+// Validate checks the timezone, the rule times and the exception dates.
+func (s Schedule) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %v", s.Timezone, err)
+	}
+	for i, rule := range s.Rules {
+		start, err := parseClock(rule.Start)
+		if err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+		if start == 24*3600 {
+			return fmt.Errorf("rule %d: start cannot be 24:00", i)
+		}
+		if _, err := parseClock(rule.End); err != nil {
+			return fmt.Errorf("rule %d: %v", i, err)
+		}
+		for _, weekday := range rule.Weekdays {
+			if weekday < time.Sunday || weekday > time.Saturday {
+				return fmt.Errorf("rule %d: invalid weekday %d", i, weekday)
+			}
+		}
+	}
+	for i, exception := range s.Exceptions {
+		if _, err := time.Parse("02-01-2006", exception.Date); err != nil {
+			return fmt.Errorf("exception %d: invalid date %q, expected DD-MM-YYYY", i, exception.Date)
+		}
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a schedule and validates it.
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	type plain Schedule
+	var decoded plain
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if err := Schedule(decoded).Validate(); err != nil {
+		return err
+	}
+	*s = Schedule(decoded)
+	return nil
+}
+
+// exception returns the exception for the local date of t, if any.
+func (s Schedule) exception(t time.Time) (ScheduleException, bool) {
+	date := t.Format("02-01-2006")
+	for _, exception := range s.Exceptions {
+		if exception.Date == date {
+			return exception, true
+		}
+	}
+	return ScheduleException{}, false
+}
+
+func hasWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, w := range weekdays {
+		if w == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the schedule is active at the Unix timestamp. Rules with
+// malformed times are ignored; use Validate to detect them.
+func (s Schedule) IsActive(timestamp int64) bool {
+	t := time.Unix(timestamp, 0).In(loadLocation(s.Timezone))
+	if exception, ok := s.exception(t); ok {
+		return exception.Active
+	}
+
+	clock := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	weekday := t.Weekday()
+	yesterday := (weekday + 6) % 7
+	for _, rule := range s.Rules {
+		start, err := parseClock(rule.Start)
+		if err != nil {
+			continue
+		}
+		end, err := parseClock(rule.End)
+		if err != nil {
+			continue
+		}
+		if end > start {
+			if hasWeekday(rule.Weekdays, weekday) && clock >= start && clock < end {
+				return true
+			}
+			continue
+		}
+		// Overnight span: the evening part today or the morning part of yesterday's rule.
+		if hasWeekday(rule.Weekdays, weekday) && clock >= start {
+			return true
+		}
+		if hasWeekday(rule.Weekdays, yesterday) && clock < end {
+			return true
+		}
+	}
+	return false
+}
+
+// NextTransition returns the first Unix timestamp after timestamp at which IsActive
+// changes. The boolean is false when the schedule never changes state again.
+func (s Schedule) NextTransition(timestamp int64) (int64, bool) {
+	loc := loadLocation(s.Timezone)
+	t := time.Unix(timestamp, 0).In(loc)
+	current := s.IsActive(timestamp)
+
+	// A weekly schedule repeats after 8 days; exceptions can push the next change further.
+	days := 8
+	for _, exception := range s.Exceptions {
+		date, err := time.ParseInLocation("02-01-2006", exception.Date, loc)
+		if err != nil || date.Unix() <= timestamp {
+			continue
+		}
+		days = max(days, int(date.Sub(t).Hours()/24)+9)
+	}
+
+	var clocks []int
+	for _, rule := range s.Rules {
+		for _, value := range []string{rule.Start, rule.End} {
+			if c, err := parseClock(value); err == nil && c < 24*3600 {
+				clocks = append(clocks, c)
+			}
+		}
+	}
+	clocks = append(clocks, 0)
+	sort.Ints(clocks)
+
+	for day := 0; day <= days; day++ {
+		for _, c := range clocks {
+			// When DST skips the wall clock time, the state flips when the clocks
+			// jump forward.
+			candidate := wallClock(t.Year(), t.Month(), t.Day()+day, c, loc).Unix()
+			if candidate <= timestamp {
+				continue
+			}
+			if s.IsActive(candidate) != current {
+				return candidate, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,202 @@
+package date
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func armedSchedule() Schedule {
+	return Schedule{
+		Timezone: "Europe/Brussels",
+		Rules: []ScheduleRule{
+			{
+				Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+				Start:    "18:00",
+				End:      "07:00",
+			},
+		},
+		Exceptions: []ScheduleException{
+			{Date: "25-12-2026", Active: true, Name: "Christmas"},
+			{Date: "16-12-2026", Active: false, Name: "Maintenance"},
+		},
+	}
+}
+
+func TestScheduleIsActive(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	schedule := armedSchedule()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected bool
+	}{
+		{"monday afternoon", time.Date(2026, 12, 7, 17, 59, 59, 0, brussels), false},
+		{"monday evening", time.Date(2026, 12, 7, 18, 0, 0, 0, brussels), true},
+		{"tuesday early morning from monday rule", time.Date(2026, 12, 8, 6, 59, 0, 0, brussels), true},
+		{"tuesday morning end", time.Date(2026, 12, 8, 7, 0, 0, 0, brussels), false},
+		{"saturday early morning from friday rule", time.Date(2026, 12, 12, 3, 0, 0, 0, brussels), true},
+		{"saturday evening", time.Date(2026, 12, 12, 20, 0, 0, 0, brussels), false},
+		{"monday early morning after weekend", time.Date(2026, 12, 14, 3, 0, 0, 0, brussels), false},
+		{"holiday armed all day", time.Date(2026, 12, 25, 12, 0, 0, 0, brussels), true},
+		{"maintenance disarmed", time.Date(2026, 12, 16, 20, 0, 0, 0, brussels), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := schedule.IsActive(tt.at.Unix()); result != tt.expected {
+				t.Errorf("IsActive(%v) = %v, want %v", tt.at, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScheduleNextTransition(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	schedule := armedSchedule()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected time.Time
+	}{
+		{"arms in the evening", time.Date(2026, 12, 7, 12, 0, 0, 0, brussels), time.Date(2026, 12, 7, 18, 0, 0, 0, brussels)},
+		{"disarms in the morning", time.Date(2026, 12, 7, 18, 0, 0, 0, brussels), time.Date(2026, 12, 8, 7, 0, 0, 0, brussels)},
+		{"over the weekend", time.Date(2026, 12, 12, 7, 0, 0, 0, brussels), time.Date(2026, 12, 14, 18, 0, 0, 0, brussels)},
+		{"maintenance day ends overnight span at midnight", time.Date(2026, 12, 15, 20, 0, 0, 0, brussels), time.Date(2026, 12, 16, 0, 0, 0, 0, brussels)},
+		{"holiday arms at midnight", time.Date(2026, 12, 24, 7, 0, 0, 0, brussels), time.Date(2026, 12, 24, 18, 0, 0, 0, brussels)},
+		{"holiday ends at midnight", time.Date(2026, 12, 25, 6, 0, 0, 0, brussels), time.Date(2026, 12, 26, 7, 0, 0, 0, brussels)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := schedule.NextTransition(tt.at.Unix())
+			if !ok || result != tt.expected.Unix() {
+				t.Errorf("NextTransition(%v) = %v, %v, want %v", tt.at, time.Unix(result, 0).In(brussels), ok, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScheduleNextTransition_DSTGap(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// 02:30 does not exist on 2026-03-29, the schedule arms when clocks jump to 03:00.
+	schedule := Schedule{
+		Timezone: "Europe/Brussels",
+		Rules:    []ScheduleRule{{Weekdays: []time.Weekday{time.Sunday}, Start: "02:30", End: "05:00"}},
+	}
+	at := time.Date(2026, 3, 29, 1, 0, 0, 0, brussels)
+	result, ok := schedule.NextTransition(at.Unix())
+	expected := time.Date(2026, 3, 29, 3, 0, 0, 0, brussels)
+	if !ok || result != expected.Unix() {
+		t.Errorf("NextTransition(%v) = %v, %v, want %v", at, time.Unix(result, 0).In(brussels), ok, expected)
+	}
+}
+
+func TestScheduleNextTransition_MidnightDSTGap(t *testing.T) {
+	// Clocks jump from 00:00 to 01:00 on these Sundays. time.Date normalizes the
+	// missing midnight backwards in Santiago and forwards in Beirut.
+	tests := []struct {
+		timezone string
+		saturday time.Time
+	}{
+		{"America/Santiago", time.Date(2022, 9, 10, 0, 0, 0, 0, time.UTC)},
+		{"Asia/Beirut", time.Date(2024, 3, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.timezone, func(t *testing.T) {
+			loc := mustLoadLocation(t, tt.timezone)
+			year, month, day := tt.saturday.Date()
+			schedule := Schedule{
+				Timezone: tt.timezone,
+				Rules:    []ScheduleRule{{Weekdays: []time.Weekday{time.Sunday}, Start: "00:00", End: "06:00"}},
+			}
+			if !schedule.IsActive(time.Date(year, month, day+1, 2, 0, 0, 0, loc).Unix()) {
+				t.Fatalf("IsActive on Sunday 02:00 = false, want true")
+			}
+
+			at := time.Date(year, month, day, 12, 0, 0, 0, loc)
+			result, ok := schedule.NextTransition(at.Unix())
+			expected := time.Date(year, month, day+1, 1, 0, 0, 0, loc)
+			if !ok || result != expected.Unix() {
+				t.Fatalf("NextTransition(%v) = %v, %v, want %v", at, time.Unix(result, 0).In(loc), ok, expected)
+			}
+			result, ok = schedule.NextTransition(result)
+			expected = time.Date(year, month, day+1, 6, 0, 0, 0, loc)
+			if !ok || result != expected.Unix() {
+				t.Errorf("NextTransition after arming = %v, %v, want %v", time.Unix(result, 0).In(loc), ok, expected)
+			}
+		})
+	}
+}
+
+func TestScheduleNextTransition_Never(t *testing.T) {
+	schedule := Schedule{Timezone: "UTC"}
+	if _, ok := schedule.NextTransition(0); ok {
+		t.Errorf("NextTransition on empty schedule expected false")
+	}
+
+	always := Schedule{
+		Timezone: "UTC",
+		Rules: []ScheduleRule{{
+			Weekdays: []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+			Start:    "00:00",
+			End:      "24:00",
+		}},
+	}
+	if !always.IsActive(1689422445) {
+		t.Errorf("IsActive on always-on schedule = false, want true")
+	}
+	if _, ok := always.NextTransition(1689422445); ok {
+		t.Errorf("NextTransition on always-on schedule expected false")
+	}
+}
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		wantErr  bool
+	}{
+		{"valid", armedSchedule(), false},
+		{"invalid timezone", Schedule{Timezone: "Mars/Olympus"}, true},
+		{"invalid start", Schedule{Timezone: "UTC", Rules: []ScheduleRule{{Start: "25:00", End: "07:00"}}}, true},
+		{"start at 24:00", Schedule{Timezone: "UTC", Rules: []ScheduleRule{{Start: "24:00", End: "07:00"}}}, true},
+		{"invalid weekday", Schedule{Timezone: "UTC", Rules: []ScheduleRule{{Weekdays: []time.Weekday{7}, Start: "08:00", End: "09:00"}}}, true},
+		{"invalid exception date", Schedule{Timezone: "UTC", Exceptions: []ScheduleException{{Date: "2026-12-25"}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleJSON(t *testing.T) {
+	schedule := armedSchedule()
+	data, err := json.Marshal(schedule)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %v", err)
+	}
+
+	var decoded Schedule
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	if decoded.Timezone != schedule.Timezone || len(decoded.Rules) != 1 || len(decoded.Exceptions) != 2 {
+		t.Errorf("json round trip = %+v, want %+v", decoded, schedule)
+	}
+	if decoded.Rules[0].Start != "18:00" || len(decoded.Rules[0].Weekdays) != 5 {
+		t.Errorf("json round trip rule = %+v, want %+v", decoded.Rules[0], schedule.Rules[0])
+	}
+
+	invalid := `{"timezone":"UTC","rules":[{"weekdays":[1],"start":"8am","end":"09:00"}]}`
+	if err := json.Unmarshal([]byte(invalid), &decoded); err == nil {
+		t.Errorf("json.Unmarshal with invalid rule expected error but got none")
+	}
+}
@@ -0,0 +1,178 @@
+package date
+
+import (
+	"sort"
+	"time"
+)
+
+// SegmentTimeline collects the segments of a continuous recording to find gaps,
+// when the camera was offline, and overlaps, such as duplicated uploads. The zero
+// value is an empty timeline.
+type SegmentTimeline struct {
+	// Segments holds the added segments in the order they were added.
+	Segments []TimeRange `json:"segments" bson:"segments"`
+}
+
+// Gap is a period without recordings.
+type Gap struct {
+	TimeRange
+	// Formatted is the length of the gap as returned by FormatDurationShortMillis.
+	Formatted string `json:"formatted" bson:"formatted"`
+}
+
+// Overlap is a period covered by two segments.
+type Overlap struct {
+	TimeRange
+	// First and Second are the indices in Segments of the overlapping segments,
+	// with First starting first.
+	First  int `json:"first" bson:"first"`
+	Second int `json:"second" bson:"second"`
+	// Formatted is the length of the overlap as returned by FormatDurationShortMillis.
+	Formatted string `json:"formatted" bson:"formatted"`
+}
+
+// DayUptime is the recorded time on a local day.
+type DayUptime struct {
+	// TimeRange is the day, clipped to the range the uptime was computed over.
+	TimeRange
+	// Date is the day in the "02-01-2006" format returned by GetDate.
+	Date     string `json:"date" bson:"date"`
+	Recorded int64  `json:"recorded" bson:"recorded"`
+	// Percentage is the recorded part of the day, between 0 and 100.
+	Percentage float64 `json:"percentage" bson:"percentage"`
+}
+
+// Add adds a segment starting at the Unix timestamp start and lasting duration
+// seconds. Segments without a positive duration are ignored.
+func (s *SegmentTimeline) Add(start int64, duration int64) {
+	if duration <= 0 {
+		return
+	}
+	s.Segments = append(s.Segments, TimeRange{Start: start, End: start + duration})
+}
+
+// Covered returns the periods covered by at least one segment, merged and sorted.
+func (s *SegmentTimeline) Covered() []TimeRange {
+	return MergeTimeRanges(s.Segments)
+}
+
+// Span returns the range from the start of the first segment to the end of the
+// last one.
+func (s *SegmentTimeline) Span() TimeRange {
+	covered := s.Covered()
+	if len(covered) == 0 {
+		return TimeRange{}
+	}
+	return TimeRange{Start: covered[0].Start, End: covered[len(covered)-1].End}
+}
+
+// Gaps returns the periods within the range that no segment covers and that last
+// longer than threshold seconds. A negative threshold reports every gap, like 0.
+// When within is empty, the span of the segments is used, so only gaps between
+// segments are reported.
+func (s *SegmentTimeline) Gaps(within TimeRange, threshold int64) []Gap {
+	if within.IsEmpty() {
+		within = s.Span()
+	}
+	var gaps []Gap
+	addGap := func(start, end int64) {
+		gap := TimeRange{Start: start, End: end}
+		if !gap.IsEmpty() && gap.Duration() > threshold {
+			gaps = append(gaps, Gap{TimeRange: gap, Formatted: formatSeconds(gap.Duration())})
+		}
+	}
+	cursor := within.Start
+	for _, r := range s.Covered() {
+		if r.End <= cursor {
+			continue
+		}
+		if r.Start >= within.End {
+			break
+		}
+		addGap(cursor, r.Start)
+		cursor = r.End
+	}
+	addGap(cursor, within.End)
+	return gaps
+}
+
+// Overlaps returns every pair of segments that share time, sorted by the start of
+// the overlap. Segments that only touch do not overlap.
+func (s *SegmentTimeline) Overlaps() []Overlap {
+	order := make([]int, len(s.Segments))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return s.Segments[order[i]].Start < s.Segments[order[j]].Start })
+
+	var overlaps []Overlap
+	for i, a := range order {
+		for _, b := range order[i+1:] {
+			if s.Segments[b].Start >= s.Segments[a].End {
+				break
+			}
+			shared, ok := s.Segments[a].Intersect(s.Segments[b])
+			if !ok {
+				continue
+			}
+			overlaps = append(overlaps, Overlap{TimeRange: shared, First: a, Second: b, Formatted: formatSeconds(shared.Duration())})
+		}
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool { return overlaps[i].Start < overlaps[j].Start })
+	return overlaps
+}
+
+// Recorded returns the number of seconds within the range covered by segments.
+func (s *SegmentTimeline) Recorded(within TimeRange) int64 {
+	return recordedWithin(s.Covered(), within)
+}
+
+// Uptime returns the percentage, between 0 and 100, of the range covered by
+// segments. An empty range has an uptime of 0.
+func (s *SegmentTimeline) Uptime(within TimeRange) float64 {
+	if within.IsEmpty() {
+		return 0
+	}
+	return 100 * float64(s.Recorded(within)) / float64(within.Duration())
+}
+
+// DailyUptime splits the range into local days in timezone and returns the uptime
+// of each. The first and last day are clipped to the range, and DST days count
+// their real 23 or 25 hours. When within is empty, the span of the segments is
+// used.
+func (s *SegmentTimeline) DailyUptime(timezone string, within TimeRange) []DayUptime {
+	if within.IsEmpty() {
+		within = s.Span()
+	}
+	covered := s.Covered()
+	loc := loadLocation(timezone)
+	days := within.Split(timezone, SplitByDay)
+	uptime := make([]DayUptime, len(days))
+	for i, day := range days {
+		recorded := recordedWithin(covered, day)
+		uptime[i] = DayUptime{
+			TimeRange:  day,
+			Date:       time.Unix(day.Start, 0).In(loc).Format("02-01-2006"),
+			Recorded:   recorded,
+			Percentage: 100 * float64(recorded) / float64(day.Duration()),
+		}
+	}
+	return uptime
+}
+
+// recordedWithin sums the overlap of sorted, merged ranges with within.
+func recordedWithin(covered []TimeRange, within TimeRange) int64 {
+	var recorded int64
+	i := sort.Search(len(covered), func(i int) bool { return covered[i].End > within.Start })
+	for ; i < len(covered) && covered[i].Start < within.End; i++ {
+		if shared, ok := covered[i].Intersect(within); ok {
+			recorded += shared.Duration()
+		}
+	}
+	return recorded
+}
+
+// formatSeconds formats a number of seconds with FormatDurationShortMillis.
+func formatSeconds(seconds int64) string {
+	return FormatDurationShortMillis(int(seconds * 1000))
+}
@@ -0,0 +1,167 @@
+package date
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSegmentTimelineGaps(t *testing.T) {
+	var timeline SegmentTimeline
+	timeline.Add(1000, 60)
+	timeline.Add(1060, 60) // touches the first segment
+	timeline.Add(1125, 60) // 5 second gap
+	timeline.Add(1485, 30) // 5 minute gap
+	timeline.Add(2000, 0)  // ignored
+
+	want := []TimeRange{{Start: 1000, End: 1120}, {Start: 1125, End: 1185}, {Start: 1485, End: 1515}}
+	if got := timeline.Covered(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Covered() = %v, want %v", got, want)
+	}
+
+	wantGaps := []Gap{{TimeRange: TimeRange{Start: 1185, End: 1485}, Formatted: "5m"}}
+	if got := timeline.Gaps(TimeRange{}, 10); !reflect.DeepEqual(got, wantGaps) {
+		t.Errorf("Gaps(10) = %v, want %v", got, wantGaps)
+	}
+	if got := timeline.Gaps(TimeRange{}, 0); len(got) != 2 || got[0].Formatted != "5s" {
+		t.Errorf("Gaps(0) = %v, want the 5s and 5m gaps", got)
+	}
+	if got := timeline.Gaps(TimeRange{}, -1); len(got) != 2 {
+		t.Errorf("Gaps(-1) = %v, want the 5s and 5m gaps without empty ones", got)
+	}
+
+	// An observation window also reports the time before and after the segments.
+	got := timeline.Gaps(TimeRange{Start: 0, End: 5000}, 10)
+	wantGaps = []Gap{
+		{TimeRange: TimeRange{Start: 0, End: 1000}, Formatted: "16m 40s"},
+		{TimeRange: TimeRange{Start: 1185, End: 1485}, Formatted: "5m"},
+		{TimeRange: TimeRange{Start: 1515, End: 5000}, Formatted: "58m 5s"},
+	}
+	if !reflect.DeepEqual(got, wantGaps) {
+		t.Errorf("Gaps(0-5000) = %v, want %v", got, wantGaps)
+	}
+	// A window inside a segment has no gaps.
+	if got := timeline.Gaps(TimeRange{Start: 1010, End: 1100}, 0); got != nil {
+		t.Errorf("Gaps inside a segment = %v, want none", got)
+	}
+}
+
+func TestSegmentTimelineOverlaps(t *testing.T) {
+	var timeline SegmentTimeline
+	timeline.Add(100, 60)
+	timeline.Add(160, 60) // touches, no overlap
+	timeline.Add(130, 20) // inside the first segment
+	timeline.Add(100, 60) // duplicated upload
+
+	want := []Overlap{
+		{TimeRange: TimeRange{Start: 100, End: 160}, First: 0, Second: 3, Formatted: "1m"},
+		{TimeRange: TimeRange{Start: 130, End: 150}, First: 0, Second: 2, Formatted: "20s"},
+		{TimeRange: TimeRange{Start: 130, End: 150}, First: 3, Second: 2, Formatted: "20s"},
+	}
+	if got := timeline.Overlaps(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Overlaps() = %+v, want %+v", got, want)
+	}
+	if got := (&SegmentTimeline{}).Overlaps(); got != nil {
+		t.Errorf("Overlaps() on empty timeline = %v, want none", got)
+	}
+}
+
+func TestSegmentTimelineUptime(t *testing.T) {
+	var timeline SegmentTimeline
+	timeline.Add(0, 30)
+	timeline.Add(20, 30) // overlapping time counts once
+	timeline.Add(75, 25)
+	window := TimeRange{Start: 0, End: 100}
+	if got := timeline.Recorded(window); got != 75 {
+		t.Errorf("Recorded() = %d, want 75", got)
+	}
+	if got := timeline.Uptime(window); got != 75 {
+		t.Errorf("Uptime() = %v, want 75", got)
+	}
+	if got := timeline.Uptime(TimeRange{}); got != 0 {
+		t.Errorf("Uptime(empty) = %v, want 0", got)
+	}
+}
+
+func TestSegmentTimelineDailyUptime(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// Clocks go forward on 26-03-2023, which only lasts 23 hours.
+	dayStart := time.Date(2023, 3, 26, 0, 0, 0, 0, brussels).Unix()
+	nextDay := time.Date(2023, 3, 27, 0, 0, 0, 0, brussels).Unix()
+
+	var timeline SegmentTimeline
+	// Recording from 22:00 on the 25th until 11:30 UTC (13:30 local) on the 26th,
+	// then the whole of the 27th.
+	timeline.Add(dayStart-2*3600, 2*3600+23*3600/2)
+	timeline.Add(nextDay, 24*3600)
+
+	window := TimeRange{Start: time.Date(2023, 3, 25, 12, 0, 0, 0, brussels).Unix(), End: nextDay + 24*3600}
+	got := timeline.DailyUptime("Europe/Brussels", window)
+	if len(got) != 3 {
+		t.Fatalf("DailyUptime() returned %d days, want 3", len(got))
+	}
+	wantDates := []string{"25-03-2023", "26-03-2023", "27-03-2023"}
+	wantPercentages := []float64{100 * 2.0 / 12, 50, 100}
+	for i, day := range got {
+		if day.Date != wantDates[i] {
+			t.Errorf("DailyUptime()[%d].Date = %q, want %q", i, day.Date, wantDates[i])
+		}
+		if math.Abs(day.Percentage-wantPercentages[i]) > 1e-9 {
+			t.Errorf("DailyUptime()[%d].Percentage = %v, want %v", i, day.Percentage, wantPercentages[i])
+		}
+	}
+	if got[1].Duration() != 23*3600 || got[1].Recorded != 23*3600/2 {
+		t.Errorf("DailyUptime()[1] = %+v, want half of a 23 hour day", got[1])
+	}
+
+	// Without a window the span of the segments is used.
+	if got := timeline.DailyUptime("Europe/Brussels", TimeRange{}); len(got) != 3 || got[0].Percentage != 100 {
+		t.Errorf("DailyUptime() over the span = %+v", got)
+	}
+}
+
+func TestSegmentTimelineDailyUptime_MidnightSpringForward(t *testing.T) {
+	santiago := mustLoadLocation(t, "America/Santiago")
+	// 11-09-2022 starts at 01:00, as clocks jump forward at midnight.
+	var timeline SegmentTimeline
+	timeline.Add(time.Date(2022, 9, 10, 12, 0, 0, 0, santiago).Unix(), 12*3600+23*3600/2)
+
+	window := TimeRange{
+		Start: time.Date(2022, 9, 10, 0, 0, 0, 0, santiago).Unix(),
+		End:   time.Date(2022, 9, 12, 0, 0, 0, 0, santiago).Unix(),
+	}
+	got := timeline.DailyUptime("America/Santiago", window)
+	if len(got) != 2 {
+		t.Fatalf("DailyUptime() returned %d days, want 2: %+v", len(got), got)
+	}
+	wantDates := []string{"10-09-2022", "11-09-2022"}
+	wantHours := []int64{24, 23}
+	for i, day := range got {
+		if day.Date != wantDates[i] || day.Duration() != wantHours[i]*3600 || day.Percentage != 50 {
+			t.Errorf("DailyUptime()[%d] = %+v, want %s lasting %d hours at 50%%", i, day, wantDates[i], wantHours[i])
+		}
+	}
+}
+
+func TestSegmentTimelineDailyUptime_MidnightSpringForwardNormalizedForwards(t *testing.T) {
+	beirut := mustLoadLocation(t, "Asia/Beirut")
+	// 31-03-2024 starts at 01:00 too, but time.Date normalizes the missing midnight
+	// forwards in Beirut.
+	start := time.Date(2024, 3, 30, 0, 0, 0, 0, beirut).Unix()
+	end := time.Date(2024, 4, 2, 0, 0, 0, 0, beirut).Unix()
+	var timeline SegmentTimeline
+	timeline.Add(start, end-start)
+
+	got := timeline.DailyUptime("Asia/Beirut", TimeRange{})
+	if len(got) != 3 {
+		t.Fatalf("DailyUptime() returned %d days, want 3: %+v", len(got), got)
+	}
+	wantDates := []string{"30-03-2024", "31-03-2024", "01-04-2024"}
+	wantHours := []int64{24, 23, 24}
+	for i, day := range got {
+		if day.Date != wantDates[i] || day.Duration() != wantHours[i]*3600 || day.Percentage != 100 {
+			t.Errorf("DailyUptime()[%d] = %+v, want %s lasting %d hours at 100%%", i, day, wantDates[i], wantHours[i])
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package date
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FrameRate is a rational frame rate in frames per second, e.g. 30000/1001 for the
+// 29.97 fps of NTSC video.
+type FrameRate struct {
+	Num int64
+	Den int64
+}
+
+// Common frame rates.
+var (
+	FPS23976 = FrameRate{Num: 24000, Den: 1001}
+	FPS24    = FrameRate{Num: 24, Den: 1}
+	FPS25    = FrameRate{Num: 25, Den: 1}
+	FPS2997  = FrameRate{Num: 30000, Den: 1001}
+	FPS30    = FrameRate{Num: 30, Den: 1}
+	FPS50    = FrameRate{Num: 50, Den: 1}
+	FPS5994  = FrameRate{Num: 60000, Den: 1001}
+	FPS60    = FrameRate{Num: 60, Den: 1}
+)
+
+// ParseFrameRate parses a frame rate written as a fraction ("30000/1001") or a
+// number ("25", "29.97"). The NTSC rates 23.976, 29.97 and 59.94 map to their exact
+// x000/1001 fractions.
+func ParseFrameRate(value string) (FrameRate, error) {
+	value = strings.TrimSpace(value)
+	if num, den, ok := strings.Cut(value, "/"); ok {
+		n, err1 := strconv.ParseInt(num, 10, 64)
+		d, err2 := strconv.ParseInt(den, 10, 64)
+		rate := FrameRate{Num: n, Den: d}
+		if err1 != nil || err2 != nil || !rate.IsValid() {
+			return FrameRate{}, fmt.Errorf("invalid frame rate %q", value)
+		}
+		return rate, nil
+	}
+	fps, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(fps) || fps <= 0 || math.IsInf(fps, 0) {
+		return FrameRate{}, fmt.Errorf("invalid frame rate %q", value)
+	}
+	rate := FrameRate{Num: int64(fps), Den: 1}
+	if nominal := math.Round(fps); nominal != fps {
+		rate = FrameRate{Num: int64(math.Round(fps * 1000)), Den: 1000}
+		// Rates just below an integer, like 29.97, are NTSC rates.
+		if ntsc := nominal * 1000 / 1001; math.Abs(fps-ntsc) < 0.01 {
+			rate = FrameRate{Num: int64(nominal) * 1000, Den: 1001}
+		}
+	}
+	if !rate.IsValid() {
+		// Rates below a thousandth of a frame per second round to 0.
+		return FrameRate{}, fmt.Errorf("invalid frame rate %q", value)
+	}
+	return rate, nil
+}
+
+// IsValid reports whether the frame rate is positive.
+func (r FrameRate) IsValid() bool {
+	return r.Num > 0 && r.Den > 0
+}
+
+// Float returns the frame rate in frames per second.
+func (r FrameRate) Float() float64 {
+	return float64(r.Num) / float64(r.Den)
+}
+
+// Nominal returns the frame rate rounded to whole frames per second, which is the
+// number of frames per second counted in a timecode (30 for 29.97).
+func (r FrameRate) Nominal() int {
+	return int(math.Round(r.Float()))
+}
+
+// String returns the frame rate as a fraction, or as an integer when the
+// denominator is 1.
+func (r FrameRate) String() string {
+	if r.Den == 1 {
+		return strconv.FormatInt(r.Num, 10)
+	}
+	return fmt.Sprintf("%d/%d", r.Num, r.Den)
+}
+
+// FrameDuration returns the duration of one frame, rounded up to the nanosecond.
+func (r FrameRate) FrameDuration() time.Duration {
+	return r.OffsetOf(1)
+}
+
+// OffsetOf returns the offset from the start of the recording at which frame
+// starts, rounded up to the nanosecond so that FrameAt returns the same frame.
+func (r FrameRate) OffsetOf(frame int64) time.Duration {
+	// frame * Den / Num seconds, split to avoid overflowing int64 nanoseconds.
+	q := frame * r.Den
+	seconds, rest := q/r.Num, q%r.Num
+	return time.Duration(seconds)*time.Second + time.Duration(-floorDiv(-rest*int64(time.Second), r.Num))
+}
+
+// FrameAt returns the number of the frame shown at offset from the start of the
+// recording, counting from 0.
+func (r FrameRate) FrameAt(offset time.Duration) int64 {
+	seconds, nanoseconds := int64(offset/time.Second), int64(offset%time.Second)
+	frames := seconds*r.Num + nanoseconds*r.Num/int64(time.Second)
+	if nanoseconds < 0 && nanoseconds*r.Num%int64(time.Second) != 0 {
+		frames--
+	}
+	return floorDiv(frames, r.Den)
+}
+
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// MPEGClockRate is the 90 kHz clock of MPEG-TS and RTP video presentation
+// timestamps.
+const MPEGClockRate = 90000
+
+// PTSToOffset converts a presentation timestamp in ticks of a clockRate Hz clock,
+// e.g. MPEGClockRate, to a duration.
+func PTSToOffset(pts, clockRate int64) time.Duration {
+	seconds, rest := pts/clockRate, pts%clockRate
+	return time.Duration(seconds)*time.Second + time.Duration(rest*int64(time.Second)/clockRate)
+}
+
+// OffsetToPTS converts a duration to a presentation timestamp in ticks of a
+// clockRate Hz clock, rounded down.
+func OffsetToPTS(offset time.Duration, clockRate int64) int64 {
+	seconds, nanoseconds := int64(offset/time.Second), int64(offset%time.Second)
+	return seconds*clockRate + floorDiv(nanoseconds*clockRate, int64(time.Second))
+}
+
+// Timecode is an SMPTE timecode. With DropFrame set, frame numbers 0 and 1 (0 to 3
+// at 59.94 fps) are skipped at the start of every minute except every tenth
+// minute, so that the timecode of 29.97 fps video keeps up with the wall clock.
+type Timecode struct {
+	Hours     int
+	Minutes   int
+	Seconds   int
+	Frames    int
+	DropFrame bool
+}
+
+// ParseTimecode parses "HH:MM:SS:FF" timecodes. A ';', '.' or ',' before the frames
+// marks a drop-frame timecode, as in "01:00:00;02".
+func ParseTimecode(value string) (Timecode, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 11 {
+		return Timecode{}, fmt.Errorf("invalid timecode %q, expected HH:MM:SS:FF", value)
+	}
+	separator := value[len(value)-3]
+	var tc Timecode
+	switch separator {
+	case ':':
+	case ';', '.', ',':
+		tc.DropFrame = true
+	default:
+		return Timecode{}, fmt.Errorf("invalid timecode %q, expected HH:MM:SS:FF", value)
+	}
+	parts := strings.Split(value[:len(value)-3], ":")
+	if len(parts) != 3 {
+		return Timecode{}, fmt.Errorf("invalid timecode %q, expected HH:MM:SS:FF", value)
+	}
+	fields := []*int{&tc.Hours, &tc.Minutes, &tc.Seconds}
+	for i, part := range parts {
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 || (i > 0 && len(part) != 2) {
+			return Timecode{}, fmt.Errorf("invalid timecode %q, expected HH:MM:SS:FF", value)
+		}
+		*fields[i] = v
+	}
+	frames, err := strconv.Atoi(value[len(value)-2:])
+	if err != nil || frames < 0 {
+		return Timecode{}, fmt.Errorf("invalid timecode %q, expected HH:MM:SS:FF", value)
+	}
+	tc.Frames = frames
+	if tc.Minutes > 59 || tc.Seconds > 59 {
+		return Timecode{}, fmt.Errorf("invalid timecode %q: minutes and seconds must be below 60", value)
+	}
+	return tc, nil
+}
+
+// String formats the timecode as "HH:MM:SS:FF", or "HH:MM:SS;FF" for drop-frame.
+func (tc Timecode) String() string {
+	separator := ":"
+	if tc.DropFrame {
+		separator = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", tc.Hours, tc.Minutes, tc.Seconds, separator, tc.Frames)
+}
+
+// dropFrames returns the number of frame numbers skipped per minute in drop-frame
+// timecode at this rate, or an error when drop-frame does not apply to it.
+func (r FrameRate) dropFrames() (int, error) {
+	nominal := r.Nominal()
+	if r.Den != 1001 || nominal%30 != 0 {
+		return 0, fmt.Errorf("drop-frame timecode is only defined for 29.97 and 59.94 fps, not %s", r)
+	}
+	return nominal / 15, nil
+}
+
+// Timecode returns the timecode of frame, counting from 0 at 00:00:00:00. Hours
+// are not wrapped at 24.
+func (r FrameRate) Timecode(frame int64, dropFrame bool) (Timecode, error) {
+	if !r.IsValid() {
+		return Timecode{}, fmt.Errorf("invalid frame rate %s", r)
+	}
+	if frame < 0 {
+		return Timecode{}, fmt.Errorf("negative frame %d", frame)
+	}
+	nominal := int64(r.Nominal())
+	if nominal == 0 {
+		return Timecode{}, fmt.Errorf("timecode needs at least 0.5 fps, not %s", r)
+	}
+	if dropFrame {
+		drop, err := r.dropFrames()
+		if err != nil {
+			return Timecode{}, err
+		}
+		d := int64(drop)
+		framesPerMinute := nominal*60 - d
+		framesPerTenMinutes := framesPerMinute*10 + d
+		tens, rest := frame/framesPerTenMinutes, frame%framesPerTenMinutes
+		// Add back the frame numbers skipped before this frame.
+		frame += 9 * d * tens
+		if rest > d {
+			frame += d * ((rest - d) / framesPerMinute)
+		}
+	}
+	return Timecode{
+		Hours:     int(frame / (nominal * 3600)),
+		Minutes:   int(frame / (nominal * 60) % 60),
+		Seconds:   int(frame / nominal % 60),
+		Frames:    int(frame % nominal),
+		DropFrame: dropFrame,
+	}, nil
+}
+
+// FrameOf returns the frame number of a timecode at this rate.
+func (r FrameRate) FrameOf(tc Timecode) (int64, error) {
+	if !r.IsValid() {
+		return 0, fmt.Errorf("invalid frame rate %s", r)
+	}
+	nominal := r.Nominal()
+	if nominal == 0 {
+		return 0, fmt.Errorf("timecode needs at least 0.5 fps, not %s", r)
+	}
+	if tc.Frames >= nominal || tc.Minutes > 59 || tc.Seconds > 59 || tc.Hours < 0 || tc.Minutes < 0 || tc.Seconds < 0 || tc.Frames < 0 {
+		return 0, fmt.Errorf("timecode %s is out of range at %s fps", tc, r)
+	}
+	frame := int64((tc.Hours*3600+tc.Minutes*60+tc.Seconds)*nominal + tc.Frames)
+	if !tc.DropFrame {
+		return frame, nil
+	}
+	drop, err := r.dropFrames()
+	if err != nil {
+		return 0, err
+	}
+	if tc.Seconds == 0 && tc.Frames < drop && tc.Minutes%10 != 0 {
+		return 0, fmt.Errorf("timecode %s does not exist in drop-frame", tc)
+	}
+	totalMinutes := int64(tc.Hours*60 + tc.Minutes)
+	return frame - int64(drop)*(totalMinutes-totalMinutes/10), nil
+}
+
+// FormatTimecode returns the timecode of the frame shown at offset from the start
+// of the recording, e.g. "00:01:00;02".
+func (r FrameRate) FormatTimecode(offset time.Duration, dropFrame bool) (string, error) {
+	tc, err := r.Timecode(r.FrameAt(offset), dropFrame)
+	if err != nil {
+		return "", err
+	}
+	return tc.String(), nil
+}
+
+// ParseTimecodeOffset returns the offset from the start of the recording of a
+// timecode string at this rate.
+func (r FrameRate) ParseTimecodeOffset(value string) (time.Duration, error) {
+	tc, err := ParseTimecode(value)
+	if err != nil {
+		return 0, err
+	}
+	frame, err := r.FrameOf(tc)
+	if err != nil {
+		return 0, err
+	}
+	return r.OffsetOf(frame), nil
+}
+
+// GetOffsetDateTime returns the wall clock time at offset into a recording that
+// started at recordingStart, formatted like GetDateTime.
+func GetOffsetDateTime(timezone string, recordingStart int64, offset time.Duration) string {
+	t := time.Unix(recordingStart, 0).Add(offset)
+	return GetDateTime(timezone, t.Unix())
+}
+
+// ParseDateTimeOffset returns the offset into a recording that started at
+// recordingStart of a wall clock time formatted like GetDateTime.
+func ParseDateTimeOffset(timezone string, recordingStart int64, dateTime string) (time.Duration, error) {
+	t, err := time.ParseInLocation("02-01-2006 - 15:04:05", dateTime, loadLocation(timezone))
+	if err != nil {
+		return 0, fmt.Errorf("invalid date time %q: %v", dateTime, err)
+	}
+	return time.Duration(t.Unix()-recordingStart) * time.Second, nil
+}
+
+// ParseFormattedDuration parses the "mm:ss" and "hh:mm:ss" strings produced by
+// FormatDuration. "<00:01" parses as 0.
+func ParseFormattedDuration(value string) (time.Duration, error) {
+	if value == "<00:01" {
+		return 0, nil
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) != 2 && len(parts) != 3 {
+		return 0, fmt.Errorf("invalid duration %q, expected mm:ss or hh:mm:ss", value)
+	}
+	var total int64
+	for i, part := range parts {
+		v, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || v < 0 || len(part) < 2 || (i > 0 && v > 59) {
+			return 0, fmt.Errorf("invalid duration %q, expected mm:ss or hh:mm:ss", value)
+		}
+		total = total*60 + v
+	}
+	return time.Duration(total) * time.Second, nil
+}
@@ -0,0 +1,208 @@
+package date
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseFrameRate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  FrameRate
+	}{
+		{"30000/1001", FPS2997},
+		{"29.97", FPS2997},
+		{"59.94", FPS5994},
+		{"23.976", FPS23976},
+		{"25", FPS25},
+		{"12.5", FrameRate{Num: 12500, Den: 1000}},
+	}
+	for _, tt := range tests {
+		got, err := ParseFrameRate(tt.input)
+		if err != nil {
+			t.Errorf("ParseFrameRate(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFrameRate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+	for _, input := range []string{"", "abc", "0", "-25", "30/0", "1/x", "NaN", "+Inf", "0.005", "0.0004"} {
+		if _, err := ParseFrameRate(input); err == nil {
+			t.Errorf("ParseFrameRate(%q) expected error", input)
+		}
+	}
+}
+
+func TestFrameRateOffsets(t *testing.T) {
+	if got := FPS2997.OffsetOf(30000); got != 1001*time.Second {
+		t.Errorf("OffsetOf(30000) = %v, want %v", got, 1001*time.Second)
+	}
+	if got := FPS25.FrameDuration(); got != 40*time.Millisecond {
+		t.Errorf("FrameDuration() = %v, want 40ms", got)
+	}
+	if got := FPS2997.FrameAt(1001 * time.Second); got != 30000 {
+		t.Errorf("FrameAt(1001s) = %d, want 30000", got)
+	}
+	// Frames round down: the offset of a frame maps back to that frame.
+	for _, frame := range []int64{0, 1, 29, 1799, 107892, 9_000_000_000} {
+		if got := FPS2997.FrameAt(FPS2997.OffsetOf(frame)); got != frame {
+			t.Errorf("FrameAt(OffsetOf(%d)) = %d", frame, got)
+		}
+	}
+	if got := FPS25.FrameAt(-time.Millisecond); got != -1 {
+		t.Errorf("FrameAt(-1ms) = %d, want -1", got)
+	}
+}
+
+func TestPTS(t *testing.T) {
+	if got := PTSToOffset(90000*3+45000, MPEGClockRate); got != 3500*time.Millisecond {
+		t.Errorf("PTSToOffset = %v, want 3.5s", got)
+	}
+	if got := OffsetToPTS(3500*time.Millisecond, MPEGClockRate); got != 315000 {
+		t.Errorf("OffsetToPTS = %d, want 315000", got)
+	}
+	// A frame at 29.97 fps lasts 3003 ticks of the 90 kHz clock.
+	if got := OffsetToPTS(FPS2997.OffsetOf(10), MPEGClockRate); got != 30029 && got != 30030 {
+		t.Errorf("OffsetToPTS(frame 10) = %d, want about 30030", got)
+	}
+}
+
+func TestFrameRateTimecode(t *testing.T) {
+	tests := []struct {
+		rate      FrameRate
+		frame     int64
+		dropFrame bool
+		want      string
+	}{
+		{FPS25, 0, false, "00:00:00:00"},
+		{FPS25, 90061, false, "01:00:02:11"},
+		{FPS2997, 1799, true, "00:00:59;29"},
+		{FPS2997, 1800, true, "00:01:00;02"},
+		{FPS2997, 3597, true, "00:01:59;29"},
+		{FPS2997, 3598, true, "00:02:00;02"},
+		{FPS2997, 17981, true, "00:09:59;29"},
+		{FPS2997, 17982, true, "00:10:00;00"},
+		{FPS2997, 17984, true, "00:10:00;02"},
+		{FPS2997, 107892, true, "01:00:00;00"},
+		{FPS2997, 107892, false, "00:59:56:12"},
+		{FPS5994, 3599, true, "00:00:59;59"},
+		{FPS5994, 3600, true, "00:01:00;04"},
+	}
+	for _, tt := range tests {
+		tc, err := tt.rate.Timecode(tt.frame, tt.dropFrame)
+		if err != nil {
+			t.Errorf("Timecode(%d) returned error: %v", tt.frame, err)
+			continue
+		}
+		if got := tc.String(); got != tt.want {
+			t.Errorf("Timecode(%d) = %q, want %q", tt.frame, got, tt.want)
+		}
+		parsed, err := ParseTimecode(tt.want)
+		if err != nil {
+			t.Errorf("ParseTimecode(%q) returned error: %v", tt.want, err)
+			continue
+		}
+		if frame, err := tt.rate.FrameOf(parsed); err != nil || frame != tt.frame {
+			t.Errorf("FrameOf(%q) = %d, %v, want %d", tt.want, frame, err, tt.frame)
+		}
+	}
+}
+
+func TestFrameRateTimecode_RoundTrip(t *testing.T) {
+	for frame := int64(0); frame < 40000; frame++ {
+		tc, err := FPS2997.Timecode(frame, true)
+		if err != nil {
+			t.Fatalf("Timecode(%d) returned error: %v", frame, err)
+		}
+		if got, err := FPS2997.FrameOf(tc); err != nil || got != frame {
+			t.Fatalf("FrameOf(Timecode(%d)) = %d, %v", frame, got, err)
+		}
+	}
+}
+
+func TestFrameRateTimecode_Invalid(t *testing.T) {
+	if _, err := FPS25.Timecode(10, true); err == nil {
+		t.Errorf("Timecode with drop-frame at 25 fps expected error")
+	}
+	if _, err := FPS2997.Timecode(-1, false); err == nil {
+		t.Errorf("Timecode(-1) expected error")
+	}
+	// Time-lapse rates below 0.5 fps have no frames per timecode second.
+	timeLapse := FrameRate{Num: 1, Den: 3}
+	if _, err := timeLapse.Timecode(10, false); err == nil {
+		t.Errorf("Timecode at 1/3 fps expected error")
+	}
+	if _, err := timeLapse.FormatTimecode(time.Minute, false); err == nil {
+		t.Errorf("FormatTimecode at 1/3 fps expected error")
+	}
+	if _, err := timeLapse.FrameOf(Timecode{Seconds: 3}); err == nil {
+		t.Errorf("FrameOf at 1/3 fps expected error")
+	}
+	// 00:01:00;00 and 00:01:00;01 are skipped in drop-frame.
+	if _, err := FPS2997.FrameOf(Timecode{Minutes: 1, Frames: 1, DropFrame: true}); err == nil {
+		t.Errorf("FrameOf(00:01:00;01) expected error")
+	}
+	if _, err := FPS25.FrameOf(Timecode{Frames: 25}); err == nil {
+		t.Errorf("FrameOf(00:00:00:25) at 25 fps expected error")
+	}
+	for _, input := range []string{"", "00:00:00", "00:00:00-00", "00:60:00:00", "0:0:0:0", "aa:00:00:00"} {
+		if _, err := ParseTimecode(input); err == nil {
+			t.Errorf("ParseTimecode(%q) expected error", input)
+		}
+	}
+}
+
+func TestFormatAndParseTimecodeOffset(t *testing.T) {
+	// Ten minutes of wall clock are exactly 17982 frames, where drop-frame timecode
+	// catches up with the clock.
+	got, err := FPS2997.FormatTimecode(10*time.Minute, true)
+	if err != nil || got != "00:10:00;00" {
+		t.Errorf("FormatTimecode(10m) = %q, %v, want %q", got, err, "00:10:00;00")
+	}
+	if got, _ := FPS2997.FormatTimecode(10*time.Minute, false); got != "00:09:59:12" {
+		t.Errorf("FormatTimecode(10m) non drop-frame = %q, want %q", got, "00:09:59:12")
+	}
+	offset, err := FPS2997.ParseTimecodeOffset("01:00:00;00")
+	if err != nil {
+		t.Fatalf("ParseTimecodeOffset returned error: %v", err)
+	}
+	// Drop-frame timecode is within a few milliseconds of wall clock after an hour.
+	if diff := offset - time.Hour; diff < -10*time.Millisecond || diff > 10*time.Millisecond {
+		t.Errorf("ParseTimecodeOffset(01:00:00;00) = %v, want about 1h", offset)
+	}
+}
+
+func TestOffsetDateTime(t *testing.T) {
+	// 2023-07-15 12:00:45 UTC
+	start := int64(1689422445)
+	if got := GetOffsetDateTime("Europe/Brussels", start, 90*time.Second+500*time.Millisecond); got != "15-07-2023 - 14:02:15" {
+		t.Errorf("GetOffsetDateTime = %q, want %q", got, "15-07-2023 - 14:02:15")
+	}
+	offset, err := ParseDateTimeOffset("Europe/Brussels", start, "15-07-2023 - 14:02:15")
+	if err != nil || offset != 90*time.Second {
+		t.Errorf("ParseDateTimeOffset = %v, %v, want 1m30s", offset, err)
+	}
+	if _, err := ParseDateTimeOffset("UTC", start, "2023-07-15"); err == nil {
+		t.Errorf("ParseDateTimeOffset with invalid layout expected error")
+	}
+}
+
+func TestParseFormattedDuration(t *testing.T) {
+	for _, seconds := range []float64{0.5, 1, 59, 61, 3599, 3600, 3661, 86399} {
+		formatted := FormatDuration(seconds)
+		got, err := ParseFormattedDuration(formatted)
+		if err != nil {
+			t.Errorf("ParseFormattedDuration(%q) returned error: %v", formatted, err)
+			continue
+		}
+		if want := time.Duration(seconds) * time.Second; got != want {
+			t.Errorf("ParseFormattedDuration(%q) = %v, want %v", formatted, got, want)
+		}
+	}
+	for _, input := range []string{"", "1", "1:00", "00:60", "aa:00", "00:00:00:00"} {
+		if _, err := ParseFormattedDuration(input); err == nil {
+			t.Errorf("ParseFormattedDuration(%q) expected error", input)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package date
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StartOfDay returns the Unix timestamp of local midnight for the day containing timestamp.
+func StartOfDay(timezone string, timestamp int64) int64 {
+	t := time.Unix(timestamp, 0).In(loadLocation(timezone))
+	return midnight(t.Year(), t.Month(), t.Day(), t.Location())
+}
+
+// EndOfDay returns the Unix timestamp of the start of the next local day, so that
+// [StartOfDay, EndOfDay) covers the whole day. This is 23, 24 or 25 hours after
+// StartOfDay depending on DST, which is why adding 86400 is not enough.
+func EndOfDay(timezone string, timestamp int64) int64 {
+	t := time.Unix(timestamp, 0).In(loadLocation(timezone))
+	return midnight(t.Year(), t.Month(), t.Day()+1, t.Location())
+}
+
+// midnight returns the Unix timestamp at which the local day starts.
+func midnight(year int, month time.Month, day int, loc *time.Location) int64 {
+	return wallClock(year, month, day, 0, loc).Unix()
+}
+
+// wallClock returns the instant at which the local clock in loc reads clock seconds
+// after midnight on the given date. When DST skips that time, it returns the
+// transition instead, the first instant after the skipped wall clock time.
+// time.Date normalizes a skipped time backwards in some zones (00:00 becomes 23:00
+// the day before in America/Santiago) and forwards in others (01:00 in
+// Asia/Beirut), so both directions are snapped to the transition.
+func wallClock(year int, month time.Month, day int, clock int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, 0, 0, clock, 0, loc)
+	want := time.Date(year, month, day, 0, 0, clock, 0, time.UTC)
+	got := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+	switch {
+	case got.Before(want):
+		_, end := t.ZoneBounds()
+		return end
+	case got.After(want):
+		start, _ := t.ZoneBounds()
+		return start
+	}
+	return t
+}
+
+// StartOfWeek returns the Unix timestamp of local midnight on the first day of the week
+// containing timestamp. The first day of the week is derived from locale, see FirstWeekday.
+func StartOfWeek(timezone string, locale string, timestamp int64) int64 {
+	t := time.Unix(timestamp, 0).In(loadLocation(timezone))
+	offset := (int(t.Weekday()) - int(FirstWeekday(locale)) + 7) % 7
+	return midnight(t.Year(), t.Month(), t.Day()-offset, t.Location())
+}
+
+// StartOfMonth returns the Unix timestamp of local midnight on the first day of the month
+// containing timestamp.
+func StartOfMonth(timezone string, timestamp int64) int64 {
+	t := time.Unix(timestamp, 0).In(loadLocation(timezone))
+	return midnight(t.Year(), t.Month(), 1, t.Location())
+}
+
+// Regions that start the week on a day other than Monday, following CLDR.
+var firstWeekdayByRegion = map[string]time.Weekday{
+	"AG": time.Sunday, "AS": time.Sunday, "BD": time.Sunday, "BR": time.Sunday, "BS": time.Sunday,
+	"BT": time.Sunday, "BW": time.Sunday, "BZ": time.Sunday, "CA": time.Sunday, "CN": time.Sunday,
+	"CO": time.Sunday, "DM": time.Sunday, "DO": time.Sunday, "ET": time.Sunday, "GT": time.Sunday,
+	"GU": time.Sunday, "HK": time.Sunday, "HN": time.Sunday, "ID": time.Sunday, "IL": time.Sunday,
+	"IN": time.Sunday, "JM": time.Sunday, "JP": time.Sunday, "KE": time.Sunday, "KH": time.Sunday,
+	"KR": time.Sunday, "LA": time.Sunday, "MH": time.Sunday, "MM": time.Sunday, "MO": time.Sunday,
+	"MT": time.Sunday, "MX": time.Sunday, "MZ": time.Sunday, "NI": time.Sunday, "NP": time.Sunday,
+	"PA": time.Sunday, "PE": time.Sunday, "PH": time.Sunday, "PK": time.Sunday, "PR": time.Sunday,
+	"PT": time.Sunday, "PY": time.Sunday, "SA": time.Sunday, "SG": time.Sunday, "SV": time.Sunday,
+	"TH": time.Sunday, "TT": time.Sunday, "TW": time.Sunday, "UM": time.Sunday, "US": time.Sunday,
+	"VE": time.Sunday, "VI": time.Sunday, "WS": time.Sunday, "YE": time.Sunday, "ZA": time.Sunday,
+	"ZW": time.Sunday,
+	"AE": time.Saturday, "AF": time.Saturday, "BH": time.Saturday, "DJ": time.Saturday, "DZ": time.Saturday,
+	"EG": time.Saturday, "IQ": time.Saturday, "IR": time.Saturday, "JO": time.Saturday, "KW": time.Saturday,
+	"LY": time.Saturday, "OM": time.Saturday, "QA": time.Saturday, "SD": time.Saturday, "SY": time.Saturday,
+	"MV": time.Friday,
+}
+
+// FirstWeekday returns the first day of the week for a locale such as "en-US", "nl_BE"
+// or a bare region "US". Locales without a known region default to Monday (ISO 8601).
+func FirstWeekday(locale string) time.Weekday {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	region := ""
+	if len(parts) == 1 && len(parts[0]) == 2 && strings.ToUpper(parts[0]) == parts[0] {
+		region = parts[0]
+	}
+	// The region is the first two letter subtag after the language, e.g. "zh-Hant-TW".
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			region = strings.ToUpper(parts[i])
+			break
+		}
+	}
+	if weekday, ok := firstWeekdayByRegion[region]; ok {
+		return weekday
+	}
+	return time.Monday
+}
+
+// TimeRange is a half-open range [Start, End) of Unix timestamps in seconds.
+type TimeRange struct {
+	Start int64 `json:"start" bson:"start"`
+	End   int64 `json:"end" bson:"end"`
+}
+
+// DayRange returns the local day containing timestamp as a TimeRange.
+func DayRange(timezone string, timestamp int64) TimeRange {
+	return TimeRange{Start: StartOfDay(timezone, timestamp), End: EndOfDay(timezone, timestamp)}
+}
+
+// GetDayRange returns the local day for a date in the "02-01-2006" format used by
+// GetTimestamp.
+func GetDayRange(timezone string, date string) (TimeRange, error) {
+	t, err := time.ParseInLocation("02-01-2006", date, loadLocation(timezone))
+	if err != nil {
+		return TimeRange{}, fmt.Errorf("invalid date %q: %v", date, err)
+	}
+	return DayRange(timezone, t.Unix()), nil
+}
+
+// Duration returns the length of the range in seconds, or 0 for an empty range.
+func (r TimeRange) Duration() int64 {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End - r.Start
+}
+
+// IsEmpty reports whether the range covers no time.
+func (r TimeRange) IsEmpty() bool {
+	return r.End <= r.Start
+}
+
+// Contains reports whether timestamp lies within [Start, End).
+func (r TimeRange) Contains(timestamp int64) bool {
+	return timestamp >= r.Start && timestamp < r.End
+}
+
+// Overlaps reports whether both ranges share at least one second.
+func (r TimeRange) Overlaps(other TimeRange) bool {
+	return !r.IsEmpty() && !other.IsEmpty() && r.Start < other.End && other.Start < r.End
+}
+
+// Intersect returns the overlapping part of both ranges. The boolean is false when
+// they do not overlap.
+func (r TimeRange) Intersect(other TimeRange) (TimeRange, bool) {
+	if !r.Overlaps(other) {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: max(r.Start, other.Start), End: min(r.End, other.End)}, true
+}
+
+// Merge returns the union of both ranges when they overlap or touch. The boolean is
+// false when there is a gap between them.
+func (r TimeRange) Merge(other TimeRange) (TimeRange, bool) {
+	if r.Start > other.End || other.Start > r.End {
+		return TimeRange{}, false
+	}
+	return TimeRange{Start: min(r.Start, other.Start), End: max(r.End, other.End)}, true
+}
+
+// MergeTimeRanges sorts the ranges and merges the ones that overlap or touch.
+// Empty ranges are dropped.
+func MergeTimeRanges(ranges []TimeRange) []TimeRange {
+	sorted := make([]TimeRange, 0, len(ranges))
+	for _, r := range ranges {
+		if !r.IsEmpty() {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	merged := make([]TimeRange, 0, len(sorted))
+	for _, r := range sorted {
+		if n := len(merged); n > 0 {
+			if union, ok := merged[n-1].Merge(r); ok {
+				merged[n-1] = union
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// SplitUnit is the granularity used to split a TimeRange.
+type SplitUnit int
+
+const (
+	SplitByHour SplitUnit = iota
+	SplitByDay
+)
+
+// Split cuts the range at local hour or day boundaries in timezone. The first and last
+// parts are clipped to the range. Days are split at local midnight, so a DST day
+// yields a 23 or 25 hour part.
+func (r TimeRange) Split(timezone string, unit SplitUnit) []TimeRange {
+	if r.IsEmpty() {
+		return nil
+	}
+	loc := loadLocation(timezone)
+	var parts []TimeRange
+	start := r.Start
+	for start < r.End {
+		end := min(nextBoundary(time.Unix(start, 0).In(loc), unit), r.End)
+		parts = append(parts, TimeRange{Start: start, End: end})
+		start = end
+	}
+	return parts
+}
+
+// nextBoundary returns the first hour or day boundary strictly after t.
+func nextBoundary(t time.Time, unit SplitUnit) int64 {
+	if unit == SplitByDay {
+		return midnight(t.Year(), t.Month(), t.Day()+1, t.Location())
+	}
+	// Subtract the local minutes and seconds rather than using time.Date, which is
+	// ambiguous for the repeated hour when clocks go back.
+	hourStart := t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second)
+	return hourStart.Add(time.Hour).Unix()
+}
+
+// Bucket is a part of a TimeRange with the number of timestamps that fell inside it.
+type Bucket struct {
+	TimeRange
+	Count int `json:"count" bson:"count"`
+}
+
+// Histogram splits the range by unit and counts the timestamps falling in each part.
+// Timestamps outside the range are ignored.
+func (r TimeRange) Histogram(timezone string, unit SplitUnit, timestamps []int64) []Bucket {
+	parts := r.Split(timezone, unit)
+	buckets := make([]Bucket, len(parts))
+	for i, part := range parts {
+		buckets[i].TimeRange = part
+	}
+	for _, ts := range timestamps {
+		if !r.Contains(ts) {
+			continue
+		}
+		i := sort.Search(len(parts), func(i int) bool { return parts[i].End > ts })
+		buckets[i].Count++
+	}
+	return buckets
+}
+
+// HourlyHistogram counts timestamps per local hour of day, as returned by GetHour.
+func HourlyHistogram(timezone string, timestamps []int64) [24]int {
+	loc := loadLocation(timezone)
+	var histogram [24]int
+	for _, ts := range timestamps {
+		histogram[time.Unix(ts, 0).In(loc).Hour()]++
+	}
+	return histogram
+}
+
+// DailyHistogram counts timestamps per local day, keyed by the date in the
+// "02-01-2006" format returned by GetDate.
+func DailyHistogram(timezone string, timestamps []int64) map[string]int {
+	loc := loadLocation(timezone)
+	histogram := make(map[string]int)
+	for _, ts := range timestamps {
+		histogram[time.Unix(ts, 0).In(loc).Format("02-01-2006")]++
+	}
+	return histogram
+}
@@ -0,0 +1,304 @@
+package date
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %q: %v", name, err)
+	}
+	return loc
+}
+
+func TestStartAndEndOfDay(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	// Santiago changes its clocks at midnight: 00:00 does not exist on 11-09-2022 and
+	// 23:00 is repeated on 02-04-2022.
+	santiago := mustLoadLocation(t, "America/Santiago")
+	// Beirut also skips 00:00 on 31-03-2024, but time.Date normalizes it forwards.
+	beirut := mustLoadLocation(t, "Asia/Beirut")
+
+	tests := []struct {
+		name          string
+		timezone      string
+		at            time.Time
+		expectedStart time.Time
+		expectedHours int64
+	}{
+		{"regular day", "Europe/Brussels", time.Date(2026, 7, 15, 13, 30, 0, 0, brussels), time.Date(2026, 7, 15, 0, 0, 0, 0, brussels), 24},
+		{"spring forward", "Europe/Brussels", time.Date(2026, 3, 29, 20, 0, 0, 0, brussels), time.Date(2026, 3, 29, 0, 0, 0, 0, brussels), 23},
+		{"fall back", "Europe/Brussels", time.Date(2026, 10, 25, 1, 0, 0, 0, brussels), time.Date(2026, 10, 25, 0, 0, 0, 0, brussels), 25},
+		{"midnight spring forward", "America/Santiago", time.Date(2022, 9, 11, 12, 0, 0, 0, santiago), time.Date(2022, 9, 11, 4, 0, 0, 0, time.UTC), 23},
+		{"day before midnight spring forward", "America/Santiago", time.Date(2022, 9, 10, 12, 0, 0, 0, santiago), time.Date(2022, 9, 10, 4, 0, 0, 0, time.UTC), 24},
+		{"midnight spring forward normalized forwards", "Asia/Beirut", time.Date(2024, 3, 31, 12, 0, 0, 0, beirut), time.Date(2024, 3, 30, 22, 0, 0, 0, time.UTC), 23},
+		{"day before midnight spring forward normalized forwards", "Asia/Beirut", time.Date(2024, 3, 30, 12, 0, 0, 0, beirut), time.Date(2024, 3, 29, 22, 0, 0, 0, time.UTC), 24},
+		{"midnight fall back", "America/Santiago", time.Date(2022, 4, 2, 12, 0, 0, 0, santiago), time.Date(2022, 4, 2, 3, 0, 0, 0, time.UTC), 25},
+		{"UTC", "UTC", time.Date(2026, 7, 15, 23, 59, 59, 0, time.UTC), time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC), 24},
+		{"unknown timezone falls back to UTC", "Mars/Olympus", time.Date(2026, 7, 15, 5, 0, 0, 0, time.UTC), time.Date(2026, 7, 15, 0, 0, 0, 0, time.UTC), 24},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := StartOfDay(tt.timezone, tt.at.Unix())
+			end := EndOfDay(tt.timezone, tt.at.Unix())
+			if start != tt.expectedStart.Unix() {
+				t.Errorf("StartOfDay(%q, %d) = %d, want %d", tt.timezone, tt.at.Unix(), start, tt.expectedStart.Unix())
+			}
+			if hours := (end - start) / 3600; hours != tt.expectedHours {
+				t.Errorf("EndOfDay - StartOfDay = %d hours, want %d", hours, tt.expectedHours)
+			}
+		})
+	}
+}
+
+func TestStartOfWeek(t *testing.T) {
+	// Wednesday 2026-07-15 12:00 UTC.
+	timestamp := time.Date(2026, 7, 15, 12, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		name     string
+		locale   string
+		expected time.Time
+	}{
+		{"belgium starts on monday", "nl-BE", time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC)},
+		{"united states starts on sunday", "en_US", time.Date(2026, 7, 12, 0, 0, 0, 0, time.UTC)},
+		{"egypt starts on saturday", "ar-EG", time.Date(2026, 7, 11, 0, 0, 0, 0, time.UTC)},
+		{"bare region", "US", time.Date(2026, 7, 12, 0, 0, 0, 0, time.UTC)},
+		{"script subtag", "zh-Hant-TW", time.Date(2026, 7, 12, 0, 0, 0, 0, time.UTC)},
+		{"language only defaults to monday", "en", time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC)},
+		{"empty locale", "", time.Date(2026, 7, 13, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := StartOfWeek("UTC", tt.locale, timestamp)
+			if result != tt.expected.Unix() {
+				t.Errorf("StartOfWeek(UTC, %q, %d) = %v, want %v", tt.locale, timestamp, time.Unix(result, 0).UTC(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestStartOfMonth(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	// 2026-07-31 20:00 UTC is already August 1st in Tokyo.
+	timestamp := time.Date(2026, 7, 31, 20, 0, 0, 0, time.UTC).Unix()
+
+	if result := StartOfMonth("Asia/Tokyo", timestamp); result != time.Date(2026, 8, 1, 0, 0, 0, 0, tokyo).Unix() {
+		t.Errorf("StartOfMonth(Asia/Tokyo) = %v, want 2026-08-01 00:00 JST", time.Unix(result, 0).In(tokyo))
+	}
+	if result := StartOfMonth("UTC", timestamp); result != time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("StartOfMonth(UTC) = %v, want 2026-07-01 00:00 UTC", time.Unix(result, 0).UTC())
+	}
+}
+
+func TestGetDayRange(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+	result, err := GetDayRange("Europe/Brussels", "29-03-2026")
+	if err != nil {
+		t.Fatalf("GetDayRange returned error: %v", err)
+	}
+	expected := TimeRange{
+		Start: time.Date(2026, 3, 29, 0, 0, 0, 0, brussels).Unix(),
+		End:   time.Date(2026, 3, 30, 0, 0, 0, 0, brussels).Unix(),
+	}
+	if result != expected {
+		t.Errorf("GetDayRange = %+v, want %+v", result, expected)
+	}
+	if result.Duration() != 23*3600 {
+		t.Errorf("GetDayRange duration = %d, want %d", result.Duration(), 23*3600)
+	}
+
+	if _, err := GetDayRange("UTC", "invalid"); err == nil {
+		t.Errorf("GetDayRange with invalid date expected error but got none")
+	}
+}
+
+func TestTimeRangeOverlapsAndIntersect(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      TimeRange
+		overlaps  bool
+		intersect TimeRange
+	}{
+		{"partial overlap", TimeRange{0, 10}, TimeRange{5, 15}, true, TimeRange{5, 10}},
+		{"contained", TimeRange{0, 10}, TimeRange{2, 4}, true, TimeRange{2, 4}},
+		{"touching", TimeRange{0, 10}, TimeRange{10, 20}, false, TimeRange{}},
+		{"disjoint", TimeRange{0, 10}, TimeRange{20, 30}, false, TimeRange{}},
+		{"empty", TimeRange{0, 10}, TimeRange{5, 5}, false, TimeRange{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.a.Overlaps(tt.b); result != tt.overlaps {
+				t.Errorf("%+v.Overlaps(%+v) = %v, want %v", tt.a, tt.b, result, tt.overlaps)
+			}
+			result, ok := tt.a.Intersect(tt.b)
+			if ok != tt.overlaps || result != tt.intersect {
+				t.Errorf("%+v.Intersect(%+v) = %+v, %v, want %+v, %v", tt.a, tt.b, result, ok, tt.intersect, tt.overlaps)
+			}
+		})
+	}
+}
+
+func TestMergeTimeRanges(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []TimeRange
+		expected []TimeRange
+	}{
+		{"empty", nil, []TimeRange{}},
+		{"unsorted overlapping", []TimeRange{{20, 30}, {0, 10}, {5, 12}}, []TimeRange{{0, 12}, {20, 30}}},
+		{"touching", []TimeRange{{0, 10}, {10, 20}}, []TimeRange{{0, 20}}},
+		{"drops empty", []TimeRange{{5, 5}, {0, 1}}, []TimeRange{{0, 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MergeTimeRanges(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("MergeTimeRanges(%v) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := (TimeRange{0, 10}).Merge(TimeRange{11, 20}); ok {
+		t.Errorf("Merge with gap expected false")
+	}
+}
+
+func TestTimeRangeSplit(t *testing.T) {
+	brussels := mustLoadLocation(t, "Europe/Brussels")
+
+	t.Run("days across spring forward", func(t *testing.T) {
+		r := TimeRange{
+			Start: time.Date(2026, 3, 28, 12, 0, 0, 0, brussels).Unix(),
+			End:   time.Date(2026, 3, 30, 12, 0, 0, 0, brussels).Unix(),
+		}
+		parts := r.Split("Europe/Brussels", SplitByDay)
+		durations := make([]int64, len(parts))
+		for i, p := range parts {
+			durations[i] = p.Duration() / 3600
+		}
+		if !reflect.DeepEqual(durations, []int64{12, 23, 12}) {
+			t.Errorf("Split by day durations = %v, want %v", durations, []int64{12, 23, 12})
+		}
+	})
+
+	t.Run("days across midnight spring forward", func(t *testing.T) {
+		santiago := mustLoadLocation(t, "America/Santiago")
+		r := TimeRange{
+			Start: time.Date(2022, 9, 10, 12, 0, 0, 0, santiago).Unix(),
+			End:   time.Date(2022, 9, 12, 12, 0, 0, 0, santiago).Unix(),
+		}
+		parts := r.Split("America/Santiago", SplitByDay)
+		durations := make([]int64, len(parts))
+		dates := make([]string, len(parts))
+		for i, p := range parts {
+			durations[i] = p.Duration() / 3600
+			dates[i] = GetDate("America/Santiago", p.Start)
+		}
+		if !reflect.DeepEqual(durations, []int64{12, 23, 12}) {
+			t.Errorf("Split by day durations = %v, want %v", durations, []int64{12, 23, 12})
+		}
+		if want := []string{"10-09-2022", "11-09-2022", "12-09-2022"}; !reflect.DeepEqual(dates, want) {
+			t.Errorf("Split by day dates = %v, want %v", dates, want)
+		}
+	})
+
+	t.Run("days across midnight spring forward normalized forwards", func(t *testing.T) {
+		beirut := mustLoadLocation(t, "Asia/Beirut")
+		r := TimeRange{
+			Start: time.Date(2024, 3, 30, 0, 0, 0, 0, beirut).Unix(),
+			End:   time.Date(2024, 4, 2, 0, 0, 0, 0, beirut).Unix(),
+		}
+		parts := r.Split("Asia/Beirut", SplitByDay)
+		durations := make([]int64, len(parts))
+		dates := make([]string, len(parts))
+		for i, p := range parts {
+			durations[i] = p.Duration() / 3600
+			dates[i] = GetDate("Asia/Beirut", p.Start)
+		}
+		if !reflect.DeepEqual(durations, []int64{24, 23, 24}) {
+			t.Errorf("Split by day durations = %v, want %v", durations, []int64{24, 23, 24})
+		}
+		if want := []string{"30-03-2024", "31-03-2024", "01-04-2024"}; !reflect.DeepEqual(dates, want) {
+			t.Errorf("Split by day dates = %v, want %v", dates, want)
+		}
+		if day, err := GetDayRange("Asia/Beirut", "31-03-2024"); err != nil || day.Duration() != 23*3600 {
+			t.Errorf("GetDayRange(31-03-2024) = %+v, %v, want a 23 hour day", day, err)
+		}
+	})
+
+	t.Run("hours across fall back", func(t *testing.T) {
+		r := DayRange("Europe/Brussels", time.Date(2026, 10, 25, 12, 0, 0, 0, brussels).Unix())
+		parts := r.Split("Europe/Brussels", SplitByHour)
+		if len(parts) != 25 {
+			t.Fatalf("Split by hour = %d parts, want 25", len(parts))
+		}
+		for _, p := range parts {
+			if p.Duration() != 3600 {
+				t.Fatalf("Split by hour part %+v has duration %d, want 3600", p, p.Duration())
+			}
+		}
+	})
+
+	t.Run("half hour offset", func(t *testing.T) {
+		kolkata := mustLoadLocation(t, "Asia/Kolkata")
+		r := TimeRange{
+			Start: time.Date(2026, 1, 1, 10, 15, 0, 0, kolkata).Unix(),
+			End:   time.Date(2026, 1, 1, 12, 0, 0, 0, kolkata).Unix(),
+		}
+		parts := r.Split("Asia/Kolkata", SplitByHour)
+		expected := []TimeRange{
+			{r.Start, time.Date(2026, 1, 1, 11, 0, 0, 0, kolkata).Unix()},
+			{time.Date(2026, 1, 1, 11, 0, 0, 0, kolkata).Unix(), r.End},
+		}
+		if !reflect.DeepEqual(parts, expected) {
+			t.Errorf("Split by hour = %v, want %v", parts, expected)
+		}
+	})
+
+	if parts := (TimeRange{10, 10}).Split("UTC", SplitByHour); parts != nil {
+		t.Errorf("Split of empty range = %v, want nil", parts)
+	}
+}
+
+func TestTimeRangeHistogram(t *testing.T) {
+	r := TimeRange{Start: 0, End: 3 * 3600}
+	timestamps := []int64{0, 10, 3599, 3600, 7300, 10800, -1}
+	buckets := r.Histogram("UTC", SplitByHour, timestamps)
+	counts := make([]int, len(buckets))
+	for i, b := range buckets {
+		counts[i] = b.Count
+	}
+	if !reflect.DeepEqual(counts, []int{3, 1, 1}) {
+		t.Errorf("Histogram counts = %v, want %v", counts, []int{3, 1, 1})
+	}
+}
+
+func TestHourlyAndDailyHistogram(t *testing.T) {
+	// 2023-07-15 12:00:45 UTC is 08:00:45 in New York, matching GetHour.
+	timestamps := []int64{1689422445, 1689422445 + 60, 1689422445 + 3600}
+
+	hourly := HourlyHistogram("America/New_York", timestamps)
+	if hourly[8] != 2 || hourly[9] != 1 {
+		t.Errorf("HourlyHistogram = %v, want 2 at hour 8 and 1 at hour 9", hourly)
+	}
+	for i, ts := range timestamps {
+		if hourly[GetHour("America/New_York", ts)] == 0 {
+			t.Errorf("HourlyHistogram disagrees with GetHour for timestamp %d", i)
+		}
+	}
+
+	daily := DailyHistogram("UTC", []int64{1689422445, 1689422445 + 86400})
+	expected := map[string]int{"15-07-2023": 1, "16-07-2023": 1}
+	if !reflect.DeepEqual(daily, expected) {
+		t.Errorf("DailyHistogram = %v, want %v", daily, expected)
+	}
+}
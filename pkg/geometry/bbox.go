@@ -0,0 +1,190 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Point is a 2D point. It is encoded as [x, y] in JSON and BSON, the same layout as
+// the centroids returned by BuildCentroids.
+type Point struct {
+	X float64
+	Y float64
+}
+
+// PointFromArray converts a centroid as returned by BuildCentroids into a Point.
+func PointFromArray(p [2]float64) Point {
+	return Point{X: p[0], Y: p[1]}
+}
+
+// Array returns the point in the [2]float64 layout used by BuildCentroids.
+func (p Point) Array() [2]float64 {
+	return [2]float64{p.X, p.Y}
+}
+
+// Add returns p translated by q.
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Sub returns the vector from q to p.
+func (p Point) Sub(q Point) Point {
+	return Point{X: p.X - q.X, Y: p.Y - q.Y}
+}
+
+// Distance returns the Euclidean distance between p and q.
+func (p Point) Distance(q Point) float64 {
+	return math.Hypot(p.X-q.X, p.Y-q.Y)
+}
+
+// MarshalJSON encodes the point as [x, y].
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]float64{p.X, p.Y})
+}
+
+// UnmarshalJSON decodes a point from [x, y].
+func (p *Point) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalJSONFloats(data, 2, "Point")
+	if err != nil {
+		return err
+	}
+	*p = Point{X: values[0], Y: values[1]}
+	return nil
+}
+
+// MarshalBSONValue encodes the point as a BSON array [x, y].
+func (p Point) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONFloats([]float64{p.X, p.Y})
+}
+
+// UnmarshalBSONValue decodes a point from a BSON array [x, y].
+func (p *Point) UnmarshalBSONValue(typ byte, data []byte) error {
+	values, err := unmarshalBSONFloats(typ, data, 2, "Point")
+	if err != nil {
+		return err
+	}
+	*p = Point{X: values[0], Y: values[1]}
+	return nil
+}
+
+// BBox is an axis-aligned bounding box stored as its top-left (X1, Y1) and
+// bottom-right (X2, Y2) corners. It is encoded as [x1, y1, x2, y2] in JSON and
+// BSON, the same layout as the entries of a traject.
+type BBox struct {
+	X1 float64
+	Y1 float64
+	X2 float64
+	Y2 float64
+}
+
+// NewBBoxXYXY returns a box from two corners, in any order.
+func NewBBoxXYXY(x1, y1, x2, y2 float64) BBox {
+	return BBox{X1: math.Min(x1, x2), Y1: math.Min(y1, y2), X2: math.Max(x1, x2), Y2: math.Max(y1, y2)}
+}
+
+// NewBBoxXYWH returns a box from its top-left corner and size.
+func NewBBoxXYWH(x, y, width, height float64) BBox {
+	return NewBBoxXYXY(x, y, x+width, y+height)
+}
+
+// NewBBoxCXCYWH returns a box from its center and size, the layout used by most
+// detection models.
+func NewBBoxCXCYWH(cx, cy, width, height float64) BBox {
+	return NewBBoxXYXY(cx-width/2, cy-height/2, cx+width/2, cy+height/2)
+}
+
+// Width returns the horizontal size of the box.
+func (b BBox) Width() float64 {
+	return b.X2 - b.X1
+}
+
+// Height returns the vertical size of the box.
+func (b BBox) Height() float64 {
+	return b.Y2 - b.Y1
+}
+
+// Area returns the area of the box, or 0 when it is empty.
+func (b BBox) Area() float64 {
+	if b.IsEmpty() {
+		return 0
+	}
+	return b.Width() * b.Height()
+}
+
+// IsEmpty reports whether the box has no area.
+func (b BBox) IsEmpty() bool {
+	return b.X2 <= b.X1 || b.Y2 <= b.Y1
+}
+
+// Center returns the center of the box, computed like BuildCentroids does.
+func (b BBox) Center() Point {
+	return Point{X: b.X1 + (b.X2-b.X1)/2, Y: b.Y1 + (b.Y2-b.Y1)/2}
+}
+
+// XYWH returns the top-left corner and size of the box.
+func (b BBox) XYWH() (float64, float64, float64, float64) {
+	return b.X1, b.Y1, b.Width(), b.Height()
+}
+
+// CXCYWH returns the center and size of the box.
+func (b BBox) CXCYWH() (float64, float64, float64, float64) {
+	c := b.Center()
+	return c.X, c.Y, b.Width(), b.Height()
+}
+
+// Scale multiplies all coordinates by sx horizontally and sy vertically, e.g. to
+// convert between frame resolutions.
+func (b BBox) Scale(sx, sy float64) BBox {
+	return NewBBoxXYXY(b.X1*sx, b.Y1*sy, b.X2*sx, b.Y2*sy)
+}
+
+// Contains reports whether p lies inside the box, borders included.
+func (b BBox) Contains(p Point) bool {
+	return p.X >= b.X1 && p.X <= b.X2 && p.Y >= b.Y1 && p.Y <= b.Y2
+}
+
+// Clip restricts the box to the frame. A box entirely outside the frame becomes
+// empty.
+func (b BBox) Clip(frame BBox) BBox {
+	return BBox{
+		X1: math.Min(math.Max(b.X1, frame.X1), frame.X2),
+		Y1: math.Min(math.Max(b.Y1, frame.Y1), frame.Y2),
+		X2: math.Max(math.Min(b.X2, frame.X2), frame.X1),
+		Y2: math.Max(math.Min(b.Y2, frame.Y2), frame.Y1),
+	}
+}
+
+// Frame returns the box covering a frame of the given size.
+func Frame(width, height float64) BBox {
+	return BBox{X2: width, Y2: height}
+}
+
+// MarshalJSON encodes the box as [x1, y1, x2, y2].
+func (b BBox) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{b.X1, b.Y1, b.X2, b.Y2})
+}
+
+// UnmarshalJSON decodes a box from [x1, y1, x2, y2].
+func (b *BBox) UnmarshalJSON(data []byte) error {
+	values, err := unmarshalJSONFloats(data, 4, "BBox")
+	if err != nil {
+		return err
+	}
+	*b = BBox{X1: values[0], Y1: values[1], X2: values[2], Y2: values[3]}
+	return nil
+}
+
+// MarshalBSONValue encodes the box as a BSON array [x1, y1, x2, y2].
+func (b BBox) MarshalBSONValue() (byte, []byte, error) {
+	return marshalBSONFloats([]float64{b.X1, b.Y1, b.X2, b.Y2})
+}
+
+// UnmarshalBSONValue decodes a box from a BSON array [x1, y1, x2, y2].
+func (b *BBox) UnmarshalBSONValue(typ byte, data []byte) error {
+	values, err := unmarshalBSONFloats(typ, data, 4, "BBox")
+	if err != nil {
+		return err
+	}
+	*b = BBox{X1: values[0], Y1: values[1], X2: values[2], Y2: values[3]}
+	return nil
+}
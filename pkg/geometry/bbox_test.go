@@ -0,0 +1,163 @@
+package geometry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestBBoxConstructors(t *testing.T) {
+	want := BBox{X1: 10, Y1: 20, X2: 50, Y2: 80}
+
+	tests := []struct {
+		name string
+		got  BBox
+	}{
+		{"xyxy", NewBBoxXYXY(10, 20, 50, 80)},
+		{"xyxy swapped corners", NewBBoxXYXY(50, 80, 10, 20)},
+		{"xywh", NewBBoxXYWH(10, 20, 40, 60)},
+		{"cxcywh", NewBBoxCXCYWH(30, 50, 40, 60)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != want {
+				t.Fatalf("%s: got %+v, want %+v", tt.name, tt.got, want)
+			}
+		})
+	}
+}
+
+func TestBBoxMeasurements(t *testing.T) {
+	b := NewBBoxXYWH(10, 20, 40, 60)
+	if b.Width() != 40 || b.Height() != 60 || b.Area() != 2400 {
+		t.Fatalf("measurements: got w=%v h=%v area=%v, want 40 60 2400", b.Width(), b.Height(), b.Area())
+	}
+	if c := b.Center(); c != (Point{X: 30, Y: 50}) {
+		t.Fatalf("center: got %+v, want {30 50}", c)
+	}
+	if x, y, w, h := b.XYWH(); x != 10 || y != 20 || w != 40 || h != 60 {
+		t.Fatalf("XYWH: got %v %v %v %v", x, y, w, h)
+	}
+	if cx, cy, w, h := b.CXCYWH(); cx != 30 || cy != 50 || w != 40 || h != 60 {
+		t.Fatalf("CXCYWH: got %v %v %v %v", cx, cy, w, h)
+	}
+	if got := b.Scale(0.5, 2); got != (BBox{X1: 5, Y1: 40, X2: 25, Y2: 160}) {
+		t.Fatalf("scale: got %+v", got)
+	}
+	if got := b.Scale(-1, 1); got != (BBox{X1: -50, Y1: 20, X2: -10, Y2: 80}) {
+		t.Fatalf("negative scale keeps corners ordered: got %+v", got)
+	}
+	if (BBox{X1: 5, Y1: 5, X2: 5, Y2: 10}).Area() != 0 {
+		t.Fatalf("degenerate box should have zero area")
+	}
+	if !b.Contains(Point{X: 10, Y: 80}) || b.Contains(Point{X: 9, Y: 50}) {
+		t.Fatalf("contains: border should be inside and outside point should not")
+	}
+}
+
+func TestBBoxClip(t *testing.T) {
+	frame := Frame(100, 50)
+
+	tests := []struct {
+		name  string
+		input BBox
+		want  BBox
+		empty bool
+	}{
+		{"inside", BBox{10, 10, 20, 20}, BBox{10, 10, 20, 20}, false},
+		{"overflowing", BBox{-10, 40, 120, 60}, BBox{0, 40, 100, 50}, false},
+		{"outside", BBox{150, 10, 200, 20}, BBox{100, 10, 100, 20}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.input.Clip(frame)
+			if got != tt.want || got.IsEmpty() != tt.empty {
+				t.Fatalf("Clip(%+v): got %+v (empty=%v), want %+v (empty=%v)", tt.input, got, got.IsEmpty(), tt.want, tt.empty)
+			}
+		})
+	}
+}
+
+func TestPointHelpers(t *testing.T) {
+	p := PointFromArray([2]float64{3, 4})
+	if p.Array() != [2]float64{3, 4} {
+		t.Fatalf("array round trip: got %v", p.Array())
+	}
+	if d := p.Distance(Point{}); d != 5 {
+		t.Fatalf("distance: got %v, want 5", d)
+	}
+	if got := p.Add(Point{1, 1}).Sub(Point{2, 2}); got != (Point{2, 3}) {
+		t.Fatalf("add/sub: got %+v, want {2 3}", got)
+	}
+}
+
+func TestBBoxAndPointJSON(t *testing.T) {
+	type detection struct {
+		Box    BBox  `json:"box"`
+		Center Point `json:"center"`
+	}
+	in := detection{Box: BBox{1, 2, 3, 4}, Center: Point{2, 3}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if string(data) != `{"box":[1,2,3,4],"center":[2,3]}` {
+		t.Fatalf("json.Marshal: got %s", data)
+	}
+	var out detection
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if out != in {
+		t.Fatalf("json round trip: got %+v, want %+v", out, in)
+	}
+
+	if err := json.Unmarshal([]byte(`{"box":[1,2,3]}`), &out); err == nil {
+		t.Fatalf("json.Unmarshal with 3 coordinates: expected error")
+	}
+	if err := json.Unmarshal([]byte(`{"center":"a"}`), &out); err == nil {
+		t.Fatalf("json.Unmarshal with string point: expected error")
+	}
+}
+
+func TestBBoxAndPointBSON(t *testing.T) {
+	type detection struct {
+		Box    BBox  `bson:"box"`
+		Center Point `bson:"center"`
+	}
+	in := detection{Box: BBox{1, 2, 3, 4}, Center: Point{2, 3}}
+	data, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var out detection
+	if err := bson.Unmarshal(data, &out); err != nil {
+		t.Fatalf("bson.Unmarshal: %v", err)
+	}
+	if out != in {
+		t.Fatalf("bson round trip: got %+v, want %+v", out, in)
+	}
+
+	// Integer coordinates, as written by other services, are accepted.
+	data, err = bson.Marshal(bson.M{"box": bson.A{int32(1), int64(2), 3.0, 4.0}, "center": bson.A{1, 2}})
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	if err := bson.Unmarshal(data, &out); err != nil {
+		t.Fatalf("bson.Unmarshal integers: %v", err)
+	}
+	if out.Box != (BBox{1, 2, 3, 4}) || out.Center != (Point{1, 2}) {
+		t.Fatalf("bson integers: got %+v", out)
+	}
+
+	data, err = bson.Marshal(bson.M{"box": "1,2,3,4"})
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	if err := bson.Unmarshal(data, &out); err == nil {
+		t.Fatalf("bson.Unmarshal string box: expected error")
+	}
+}
@@ -0,0 +1,131 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// CompressedPath is the result of CompressCentroidsOrdered.
+type CompressedPath struct {
+	// Centroids are the kept points, in their original order.
+	Centroids [][2]float64
+	// Timestamps are the timestamps of the kept points, or nil when none were given.
+	Timestamps []int64
+	// Indices are the positions of the kept points in the input.
+	Indices []int
+	// OriginalLength is the path length of the input.
+	OriginalLength float64
+	// CompressedLength is the path length of the kept points.
+	CompressedLength float64
+}
+
+// LengthLost returns the path length removed by the compression.
+func (p CompressedPath) LengthLost() float64 {
+	return p.OriginalLength - p.CompressedLength
+}
+
+// LengthLostRatio returns the fraction of the original path length that was
+// removed, or 0 for a path without length.
+func (p CompressedPath) LengthLostRatio() float64 {
+	if p.OriginalLength == 0 {
+		return 0
+	}
+	return p.LengthLost() / p.OriginalLength
+}
+
+// PathLength returns the length of the polyline through points.
+func PathLength(points [][2]float64) float64 {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		length += math.Hypot(points[i][0]-points[i-1][0], points[i][1]-points[i-1][1])
+	}
+	return length
+}
+
+// CompressCentroidsOrdered reduces a trajectory to at most maxPoints points like
+// CompressCentroids, but keeps the temporal order of the path: consecutive points
+// in the same grid cell are merged, so a path that revisits a cell keeps its later
+// segments, and the first and last points are always kept. Timestamps are optional;
+// when given they must have one entry per centroid and are reduced alongside.
+// maxPoints <= 0 keeps every point; a maxPoints of 1 is raised to 2 so that both
+// endpoints fit.
+func CompressCentroidsOrdered(centroids [][2]float64, timestamps []int64, maxPoints int) (CompressedPath, error) {
+	if timestamps != nil && len(timestamps) != len(centroids) {
+		return CompressedPath{}, fmt.Errorf("got %d timestamps for %d centroids", len(timestamps), len(centroids))
+	}
+	if maxPoints == 1 {
+		maxPoints = 2
+	}
+
+	indices := make([]int, len(centroids))
+	for i := range indices {
+		indices[i] = i
+	}
+	if maxPoints > 0 && len(centroids) > maxPoints {
+		indices = mergeCellRuns(centroids, maxPoints)
+		if len(indices) > maxPoints {
+			indices = downsampleIndices(indices, maxPoints)
+		}
+	}
+
+	path := CompressedPath{
+		Centroids:      make([][2]float64, len(indices)),
+		Indices:        indices,
+		OriginalLength: PathLength(centroids),
+	}
+	if timestamps != nil {
+		path.Timestamps = make([]int64, len(indices))
+	}
+	for i, index := range indices {
+		path.Centroids[i] = centroids[index]
+		if timestamps != nil {
+			path.Timestamps[i] = timestamps[index]
+		}
+	}
+	path.CompressedLength = PathLength(path.Centroids)
+	return path, nil
+}
+
+// mergeCellRuns returns the indices of the points that enter a new grid cell, using
+// the same cell size as CompressCentroids, plus the last point.
+func mergeCellRuns(centroids [][2]float64, maxPoints int) []int {
+	bounds := CentroidBounds(centroids)
+	minX, minY := bounds.X1, bounds.Y1
+	width, height := bounds.Width(), bounds.Height()
+	last := len(centroids) - 1
+	if width == 0 && height == 0 {
+		return []int{0, last}
+	}
+	cellSize := math.Sqrt((width * height) / float64(maxPoints))
+	if cellSize <= 0 {
+		cellSize = math.Max(width, height) / float64(maxPoints)
+	}
+
+	cell := func(c [2]float64) [2]int {
+		return [2]int{int(math.Floor((c[0] - minX) / cellSize)), int(math.Floor((c[1] - minY) / cellSize))}
+	}
+	indices := []int{0}
+	current := cell(centroids[0])
+	for i := 1; i < last; i++ {
+		if key := cell(centroids[i]); key != current {
+			indices = append(indices, i)
+			current = key
+		}
+	}
+	return append(indices, last)
+}
+
+// downsampleIndices picks maxPoints evenly spaced entries, including the first and
+// the last.
+func downsampleIndices(indices []int, maxPoints int) []int {
+	step := float64(len(indices)-1) / float64(maxPoints-1)
+	downsampled := make([]int, 0, maxPoints)
+	for i := 0; i < maxPoints; i++ {
+		idx := int(math.Round(float64(i) * step))
+		if idx >= len(indices) {
+			idx = len(indices) - 1
+		}
+		downsampled = append(downsampled, indices[idx])
+	}
+	return downsampled
+}
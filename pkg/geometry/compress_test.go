@@ -0,0 +1,104 @@
+package geometry
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// outAndBack walks right along y=0 and comes back along y=1, revisiting the cells
+// of the outward leg.
+func outAndBack() ([][2]float64, []int64) {
+	var centroids [][2]float64
+	var timestamps []int64
+	for i := 0; i <= 50; i++ {
+		centroids = append(centroids, [2]float64{float64(i), 0})
+	}
+	for i := 50; i >= 0; i-- {
+		centroids = append(centroids, [2]float64{float64(i), 1})
+	}
+	for i := range centroids {
+		timestamps = append(timestamps, int64(1000+i))
+	}
+	return centroids, timestamps
+}
+
+func TestPathLength(t *testing.T) {
+	if got := PathLength([][2]float64{{0, 0}, {3, 4}, {3, 0}}); got != 9 {
+		t.Fatalf("PathLength: got %v, want 9", got)
+	}
+	if got := PathLength(nil); got != 0 {
+		t.Fatalf("PathLength(nil): got %v, want 0", got)
+	}
+}
+
+func TestCompressCentroidsOrdered_KeepsEndpointsAndRevisits(t *testing.T) {
+	centroids, timestamps := outAndBack()
+
+	// CompressCentroids drops the return leg and the final position.
+	if got := CompressCentroids(centroids, 10); got[len(got)-1] == centroids[len(centroids)-1] {
+		t.Fatalf("expected CompressCentroids to lose the final position, got %v", got)
+	}
+
+	path, err := CompressCentroidsOrdered(centroids, timestamps, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(path.Centroids) > 10 {
+		t.Fatalf("got %d points, want at most 10", len(path.Centroids))
+	}
+	if path.Centroids[0] != centroids[0] || path.Centroids[len(path.Centroids)-1] != centroids[len(centroids)-1] {
+		t.Fatalf("endpoints not kept: %v", path.Centroids)
+	}
+	for i := 1; i < len(path.Indices); i++ {
+		if path.Indices[i] <= path.Indices[i-1] {
+			t.Fatalf("indices not in order: %v", path.Indices)
+		}
+	}
+	for i, index := range path.Indices {
+		if path.Timestamps[i] != timestamps[index] || path.Centroids[i] != centroids[index] {
+			t.Fatalf("entry %d does not match input index %d", i, index)
+		}
+	}
+	// The path reaches x=50 and comes back, so it keeps most of its length.
+	if path.OriginalLength != 101 {
+		t.Fatalf("OriginalLength: got %v, want 101", path.OriginalLength)
+	}
+	if ratio := path.LengthLostRatio(); ratio < 0 || ratio > 0.25 {
+		t.Fatalf("LengthLostRatio: got %v, want within [0, 0.25]", ratio)
+	}
+	if math.Abs(path.LengthLost()-(path.OriginalLength-path.CompressedLength)) > 1e-12 {
+		t.Fatalf("LengthLost inconsistent with lengths")
+	}
+}
+
+func TestCompressCentroidsOrdered_Unchanged(t *testing.T) {
+	input := [][2]float64{{0, 0}, {1, 1}, {2, 2}}
+	for _, maxPoints := range []int{0, -1, 3, 10} {
+		path, err := CompressCentroidsOrdered(input, nil, maxPoints)
+		if err != nil {
+			t.Fatalf("maxPoints=%d: unexpected error: %v", maxPoints, err)
+		}
+		if !reflect.DeepEqual(path.Centroids, input) || path.Timestamps != nil || path.LengthLost() != 0 {
+			t.Fatalf("maxPoints=%d: got %+v", maxPoints, path)
+		}
+	}
+}
+
+func TestCompressCentroidsOrdered_EdgeCases(t *testing.T) {
+	identical := [][2]float64{{1, 1}, {1, 1}, {1, 1}, {1, 1}}
+	path, _ := CompressCentroidsOrdered(identical, nil, 2)
+	if !reflect.DeepEqual(path.Indices, []int{0, 3}) {
+		t.Fatalf("identical points: got indices %v, want [0 3]", path.Indices)
+	}
+
+	centroids, _ := outAndBack()
+	path, _ = CompressCentroidsOrdered(centroids, nil, 1)
+	if !reflect.DeepEqual(path.Indices, []int{0, len(centroids) - 1}) {
+		t.Fatalf("maxPoints=1: got indices %v, want both endpoints", path.Indices)
+	}
+
+	if _, err := CompressCentroidsOrdered(centroids, []int64{1, 2}, 10); err == nil {
+		t.Fatalf("expected error for mismatched timestamps")
+	}
+}
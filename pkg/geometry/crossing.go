@@ -0,0 +1,102 @@
+package geometry
+
+// CrossingDirection tells on which side of a Tripwire an object ended up.
+type CrossingDirection string
+
+const (
+	// CrossingIn is a crossing from the left-hand side of the tripwire to its
+	// right-hand side, looking from A towards B on screen (y pointing down).
+	CrossingIn CrossingDirection = "in"
+	// CrossingOut is a crossing from the right-hand side to the left-hand side.
+	CrossingOut CrossingDirection = "out"
+)
+
+// Tripwire is a directed virtual line from A to B used to count objects. A
+// trajectory only changes side once it moves further than Hysteresis away from the
+// line, so jitter around the line does not produce extra crossings.
+type Tripwire struct {
+	A          Point   `json:"a" bson:"a"`
+	B          Point   `json:"b" bson:"b"`
+	Hysteresis float64 `json:"hysteresis" bson:"hysteresis"`
+}
+
+// Crossing is a confirmed passage of a trajectory through a Tripwire.
+type Crossing struct {
+	// Index is the trajectory index of the first point after the line was crossed,
+	// i.e. the crossing happened on the segment from Index-1 to Index.
+	Index int `json:"index" bson:"index"`
+	// Position is the interpolated point where the trajectory crossed the line.
+	Position  Point             `json:"position" bson:"position"`
+	Direction CrossingDirection `json:"direction" bson:"direction"`
+}
+
+// signedDistance returns the distance from p to the tripwire line, positive on its
+// right-hand side on screen.
+func (w Tripwire) signedDistance(p Point, length float64) float64 {
+	return ((w.B.X-w.A.X)*(p.Y-w.A.Y) - (w.B.Y-w.A.Y)*(p.X-w.A.X)) / length
+}
+
+// Crossings returns the crossings of an ordered trajectory, such as the centroids
+// returned by BuildCentroids, through the tripwire segment. Passing the line beside
+// the segment changes the side without producing a crossing.
+func (w Tripwire) Crossings(trajectory [][2]float64) []Crossing {
+	length := w.A.Distance(w.B)
+	if length == 0 {
+		return nil
+	}
+	direction := w.B.Sub(w.A)
+
+	var crossings []Crossing
+	side := 0
+	lastIndex := -1
+	var lastPosition Point
+	var previous Point
+	previousDistance := 0.0
+	for i, c := range trajectory {
+		p := PointFromArray(c)
+		distance := w.signedDistance(p, length)
+
+		if i > 0 && ((previousDistance <= 0 && distance > 0) || (previousDistance >= 0 && distance < 0)) {
+			f := previousDistance / (previousDistance - distance)
+			lastIndex = i
+			lastPosition = Point{X: previous.X + f*(p.X-previous.X), Y: previous.Y + f*(p.Y-previous.Y)}
+		}
+
+		if distance != 0 && (distance >= w.Hysteresis || distance <= -w.Hysteresis) {
+			newSide := 1
+			if distance < 0 {
+				newSide = -1
+			}
+			if side != 0 && newSide != side && lastIndex >= 0 {
+				// Only count passages through the segment itself.
+				t := ((lastPosition.X-w.A.X)*direction.X + (lastPosition.Y-w.A.Y)*direction.Y) / (length * length)
+				if t >= 0 && t <= 1 {
+					crossing := Crossing{Index: lastIndex, Position: lastPosition, Direction: CrossingIn}
+					if newSide < 0 {
+						crossing.Direction = CrossingOut
+					}
+					crossings = append(crossings, crossing)
+				}
+			}
+			side = newSide
+			lastIndex = -1
+		}
+
+		previous = p
+		previousDistance = distance
+	}
+	return crossings
+}
+
+// Count returns the number of in and out crossings of a trajectory.
+func (w Tripwire) Count(trajectory [][2]float64) (int, int) {
+	in, out := 0, 0
+	for _, crossing := range w.Crossings(trajectory) {
+		if crossing.Direction == CrossingIn {
+			in++
+		} else {
+			out++
+		}
+	}
+	return in, out
+}
@@ -0,0 +1,106 @@
+package geometry
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// A horizontal tripwire pointing right; on screen its right-hand side is below it.
+var horizontalWire = Tripwire{A: Point{0, 50}, B: Point{100, 50}}
+
+func TestTripwireCrossings_Basic(t *testing.T) {
+	trajectory := [][2]float64{{40, 10}, {40, 30}, {50, 60}, {50, 90}}
+
+	got := horizontalWire.Crossings(trajectory)
+	want := []Crossing{{Index: 2, Position: Point{X: 40 + 10*(20.0/30.0), Y: 50}, Direction: CrossingIn}}
+	if len(got) != 1 || got[0].Index != want[0].Index || got[0].Direction != want[0].Direction {
+		t.Fatalf("Crossings: got %+v, want %+v", got, want)
+	}
+	if math.Abs(got[0].Position.X-want[0].Position.X) > 1e-9 || math.Abs(got[0].Position.Y-50) > 1e-9 {
+		t.Fatalf("Crossings position: got %+v, want %+v", got[0].Position, want[0].Position)
+	}
+
+	reversed := make([][2]float64, len(trajectory))
+	for i := range trajectory {
+		reversed[i] = trajectory[len(trajectory)-1-i]
+	}
+	if got := horizontalWire.Crossings(reversed); len(got) != 1 || got[0].Direction != CrossingOut || got[0].Index != 2 {
+		t.Fatalf("reversed Crossings: got %+v", got)
+	}
+}
+
+func TestTripwireCrossings_OutsideSegment(t *testing.T) {
+	trajectory := [][2]float64{{150, 10}, {150, 90}}
+	if got := horizontalWire.Crossings(trajectory); len(got) != 0 {
+		t.Fatalf("passing beside the wire: got %+v, want none", got)
+	}
+
+	// Going around the end and then back through the wire counts once, as out.
+	trajectory = [][2]float64{{150, 10}, {150, 90}, {50, 90}, {50, 10}}
+	if got := horizontalWire.Crossings(trajectory); len(got) != 1 || got[0].Direction != CrossingOut {
+		t.Fatalf("around and back through: got %+v", got)
+	}
+}
+
+func TestTripwireCrossings_Hysteresis(t *testing.T) {
+	// Jitter around the line before finally moving away downwards.
+	trajectory := [][2]float64{{10, 20}, {20, 49}, {30, 51}, {40, 49}, {50, 52}, {60, 48}, {70, 53}, {80, 80}}
+
+	noHysteresis := horizontalWire
+	in, out := noHysteresis.Count(trajectory)
+	if in != 3 || out != 2 {
+		t.Fatalf("without hysteresis: got in=%d out=%d, want in=3 out=2", in, out)
+	}
+
+	withHysteresis := horizontalWire
+	withHysteresis.Hysteresis = 5
+	got := withHysteresis.Crossings(trajectory)
+	if len(got) != 1 || got[0].Direction != CrossingIn {
+		t.Fatalf("with hysteresis: got %+v, want a single in crossing", got)
+	}
+	// The reported crossing is the last passage through the line before confirmation.
+	if got[0].Index != 6 {
+		t.Fatalf("with hysteresis: got index %d, want 6", got[0].Index)
+	}
+
+	// Never leaving the band produces nothing.
+	if got := withHysteresis.Crossings(trajectory[1:7]); len(got) != 0 {
+		t.Fatalf("inside band only: got %+v", got)
+	}
+}
+
+func TestTripwireCrossings_Degenerate(t *testing.T) {
+	if got := (Tripwire{A: Point{1, 1}, B: Point{1, 1}}).Crossings([][2]float64{{0, 0}, {2, 2}}); got != nil {
+		t.Fatalf("zero length wire: got %+v", got)
+	}
+	if got := horizontalWire.Crossings(nil); got != nil {
+		t.Fatalf("empty trajectory: got %+v", got)
+	}
+	// Touching the line and returning is not a crossing.
+	trajectory := [][2]float64{{50, 40}, {50, 50}, {50, 40}}
+	if got := horizontalWire.Crossings(trajectory); len(got) != 0 {
+		t.Fatalf("touching: got %+v", got)
+	}
+	// Stepping onto the line and then continuing is a crossing at the touching point.
+	trajectory = [][2]float64{{50, 40}, {50, 50}, {50, 60}}
+	want := []Crossing{{Index: 2, Position: Point{50, 50}, Direction: CrossingIn}}
+	if got := horizontalWire.Crossings(trajectory); !reflect.DeepEqual(got, want) {
+		t.Fatalf("through the line: got %+v, want %+v", got, want)
+	}
+}
+
+func TestTripwireCrossings_BuildCentroids(t *testing.T) {
+	// A vertical wire in the middle of the normalized view, pointing down: its
+	// right-hand side on screen is the left half of the view.
+	wire := Tripwire{A: Point{50, 0}, B: Point{50, 100}, Hysteresis: 2}
+	traject := []interface{}{
+		[]interface{}{1500.0, 500.0, 1600.0, 600.0},
+		[]interface{}{900.0, 500.0, 1000.0, 600.0},
+		[]interface{}{300.0, 500.0, 400.0, 600.0},
+	}
+	in, out := wire.Count(BuildCentroids(traject, 1920, 1080))
+	if in != 1 || out != 0 {
+		t.Fatalf("right to left: got in=%d out=%d, want in=1 out=0", in, out)
+	}
+}
@@ -0,0 +1,236 @@
+package geometry
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+)
+
+// DensityGrid accumulates centroids in the normalized 0-100 space of BuildCentroids
+// into a Width x Height grid, e.g. to render where objects walk. Cells are stored
+// row by row.
+type DensityGrid struct {
+	Width  int
+	Height int
+	Cells  []float64
+}
+
+// NewDensityGrid returns an empty grid of the given size.
+func NewDensityGrid(width, height int) (*DensityGrid, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid density grid size %dx%d", width, height)
+	}
+	return &DensityGrid{Width: width, Height: height, Cells: make([]float64, width*height)}, nil
+}
+
+// At returns the value of the cell in column x and row y.
+func (g *DensityGrid) At(x, y int) float64 {
+	return g.Cells[y*g.Width+x]
+}
+
+// cell returns the grid cell of a normalized centroid. Centroids outside the 0-100
+// space are reported as not ok.
+func (g *DensityGrid) cell(c [2]float64) (int, int, bool) {
+	if c[0] < 0 || c[0] > 100 || c[1] < 0 || c[1] > 100 || math.IsNaN(c[0]) || math.IsNaN(c[1]) {
+		return 0, 0, false
+	}
+	x := min(int(c[0]*float64(g.Width)/100), g.Width-1)
+	y := min(int(c[1]*float64(g.Height)/100), g.Height-1)
+	return x, y, true
+}
+
+// AddCentroids adds one to the cell of every centroid. Centroids outside the 0-100
+// space are ignored.
+func (g *DensityGrid) AddCentroids(centroids [][2]float64) {
+	for _, c := range centroids {
+		if x, y, ok := g.cell(c); ok {
+			g.Cells[y*g.Width+x]++
+		}
+	}
+}
+
+// AddTrajectory adds one to every cell the path through centroids passes, so that
+// fast objects with few detections still leave a continuous trace. A cell shared
+// by two consecutive segments is counted once. Segments with an end outside the
+// 0-100 space are ignored.
+func (g *DensityGrid) AddTrajectory(centroids [][2]float64) {
+	if len(centroids) == 1 {
+		g.AddCentroids(centroids)
+		return
+	}
+	// The first cell of a segment is the last cell of the previous one, so it is
+	// only added when the previous segment was not drawn.
+	previousDrawn := false
+	for i := 1; i < len(centroids); i++ {
+		x0, y0, ok0 := g.cell(centroids[i-1])
+		x1, y1, ok1 := g.cell(centroids[i])
+		if !ok0 || !ok1 {
+			previousDrawn = false
+			continue
+		}
+		g.addLine(x0, y0, x1, y1, previousDrawn)
+		previousDrawn = true
+	}
+}
+
+// addLine adds one to the cells on the line from (x0, y0) to (x1, y1) with
+// Bresenham's algorithm.
+func (g *DensityGrid) addLine(x0, y0, x1, y1 int, skipFirst bool) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for first := true; ; first = false {
+		if !first || !skipFirst {
+			g.Cells[y0*g.Width+x0]++
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// Total returns the sum of all cells.
+func (g *DensityGrid) Total() float64 {
+	total := 0.0
+	for _, v := range g.Cells {
+		total += v
+	}
+	return total
+}
+
+// Max returns the highest cell value.
+func (g *DensityGrid) Max() float64 {
+	maxValue := 0.0
+	for _, v := range g.Cells {
+		maxValue = math.Max(maxValue, v)
+	}
+	return maxValue
+}
+
+// Blur returns a copy of the grid convolved with a Gaussian kernel of standard
+// deviation sigma, in cells. Density blurred beyond the borders is lost. A sigma
+// of 0 or less returns an unchanged copy.
+func (g *DensityGrid) Blur(sigma float64) *DensityGrid {
+	blurred := &DensityGrid{Width: g.Width, Height: g.Height, Cells: append([]float64(nil), g.Cells...)}
+	if sigma <= 0 {
+		return blurred
+	}
+	radius := int(math.Ceil(3 * sigma))
+	kernel := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range kernel {
+		d := float64(i - radius)
+		kernel[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += kernel[i]
+	}
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	// The kernel is separable: blur the rows, then the columns.
+	tmp := make([]float64, len(g.Cells))
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			v := 0.0
+			for k, weight := range kernel {
+				if xx := x + k - radius; xx >= 0 && xx < g.Width {
+					v += weight * blurred.Cells[y*g.Width+xx]
+				}
+			}
+			tmp[y*g.Width+x] = v
+		}
+	}
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			v := 0.0
+			for k, weight := range kernel {
+				if yy := y + k - radius; yy >= 0 && yy < g.Height {
+					v += weight * tmp[yy*g.Width+x]
+				}
+			}
+			blurred.Cells[y*g.Width+x] = v
+		}
+	}
+	return blurred
+}
+
+// Normalize returns a copy of the grid scaled so that the highest cell is 1. An
+// empty grid stays all zeros.
+func (g *DensityGrid) Normalize() *DensityGrid {
+	normalized := &DensityGrid{Width: g.Width, Height: g.Height, Cells: make([]float64, len(g.Cells))}
+	if maxValue := g.Max(); maxValue > 0 {
+		for i, v := range g.Cells {
+			normalized.Cells[i] = v / maxValue
+		}
+	}
+	return normalized
+}
+
+// heatStops is the color ramp of Image, from low to high density.
+var heatStops = []color.NRGBA{
+	{0, 0, 255, 255},
+	{0, 255, 255, 255},
+	{0, 255, 0, 255},
+	{255, 255, 0, 255},
+	{255, 0, 0, 255},
+}
+
+// heatColor returns the ramp color of a value in [0, 1]. The opacity grows with the
+// value so the image can be drawn over a video frame.
+func heatColor(v float64) color.NRGBA {
+	v = math.Max(0, math.Min(1, v))
+	if v == 0 {
+		return color.NRGBA{}
+	}
+	position := v * float64(len(heatStops)-1)
+	i := min(int(position), len(heatStops)-2)
+	f := position - float64(i)
+	a, b := heatStops[i], heatStops[i+1]
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + f*(float64(y)-float64(x))))
+	}
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: uint8(math.Round(v * 255))}
+}
+
+// Image renders the grid relative to its highest cell on a blue to red ramp, with
+// empty cells fully transparent. Each cell becomes one pixel.
+func (g *DensityGrid) Image() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, g.Width, g.Height))
+	normalized := g.Normalize()
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			img.SetNRGBA(x, y, heatColor(normalized.At(x, y)))
+		}
+	}
+	return img
+}
+
+// EncodePNG writes the image of the grid as a PNG.
+func (g *DensityGrid) EncodePNG(w io.Writer) error {
+	return png.Encode(w, g.Image())
+}
@@ -0,0 +1,129 @@
+package geometry
+
+import (
+	"bytes"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func mustDensityGrid(t *testing.T, width, height int) *DensityGrid {
+	t.Helper()
+	grid, err := NewDensityGrid(width, height)
+	if err != nil {
+		t.Fatalf("NewDensityGrid(%d, %d) returned error: %v", width, height, err)
+	}
+	return grid
+}
+
+func TestNewDensityGrid_InvalidSize(t *testing.T) {
+	if _, err := NewDensityGrid(0, 10); err == nil {
+		t.Fatalf("expected error for zero width")
+	}
+}
+
+func TestDensityGrid_AddCentroids(t *testing.T) {
+	grid := mustDensityGrid(t, 10, 5)
+	grid.AddCentroids([][2]float64{{0, 0}, {5, 5}, {100, 100}, {55, 50}, {-1, 50}, {50, 101}})
+
+	if grid.At(0, 0) != 2 {
+		t.Fatalf("cell (0,0): got %v, want 2", grid.At(0, 0))
+	}
+	if grid.At(9, 4) != 1 || grid.At(5, 2) != 1 {
+		t.Fatalf("cells (9,4) and (5,2): got %v and %v, want 1", grid.At(9, 4), grid.At(5, 2))
+	}
+	if grid.Total() != 4 {
+		t.Fatalf("Total: got %v, want 4 (outside centroids ignored)", grid.Total())
+	}
+}
+
+func TestDensityGrid_AddTrajectory(t *testing.T) {
+	grid := mustDensityGrid(t, 10, 10)
+	// Two detections far apart still draw a continuous horizontal line.
+	grid.AddTrajectory([][2]float64{{5, 55}, {95, 55}})
+	for x := 0; x < 10; x++ {
+		if grid.At(x, 5) != 1 {
+			t.Fatalf("cell (%d,5): got %v, want 1", x, grid.At(x, 5))
+		}
+	}
+	if grid.Total() != 10 {
+		t.Fatalf("Total: got %v, want 10", grid.Total())
+	}
+
+	// A joint between segments is counted once, a diagonal covers one cell per step.
+	grid = mustDensityGrid(t, 10, 10)
+	grid.AddTrajectory([][2]float64{{5, 5}, {45, 45}, {45, 95}})
+	if grid.Total() != 5+5 {
+		t.Fatalf("Total: got %v, want 10", grid.Total())
+	}
+	if grid.At(4, 4) != 1 {
+		t.Fatalf("joint cell: got %v, want 1", grid.At(4, 4))
+	}
+
+	grid = mustDensityGrid(t, 10, 10)
+	grid.AddTrajectory([][2]float64{{55, 55}})
+	if grid.At(5, 5) != 1 || grid.Total() != 1 {
+		t.Fatalf("single point trajectory not added")
+	}
+}
+
+func TestDensityGrid_Blur(t *testing.T) {
+	grid := mustDensityGrid(t, 21, 21)
+	grid.AddCentroids([][2]float64{{50, 50}})
+
+	blurred := grid.Blur(2)
+	if math.Abs(blurred.Total()-1) > 1e-9 {
+		t.Fatalf("Blur should keep the mass away from borders: got %v", blurred.Total())
+	}
+	center := blurred.At(10, 10)
+	if center >= 1 || center <= blurred.At(11, 10) || blurred.At(11, 10) <= blurred.At(12, 10) {
+		t.Fatalf("Blur should spread the peak: center %v", center)
+	}
+	if blurred.At(9, 10) != blurred.At(11, 10) || blurred.At(10, 9) != blurred.At(10, 11) {
+		t.Fatalf("Blur should be symmetric")
+	}
+	if grid.At(10, 10) != 1 {
+		t.Fatalf("Blur modified the original grid")
+	}
+	if unchanged := grid.Blur(0); unchanged.At(10, 10) != 1 {
+		t.Fatalf("Blur(0) changed the grid")
+	}
+}
+
+func TestDensityGrid_Normalize(t *testing.T) {
+	grid := mustDensityGrid(t, 4, 1)
+	grid.AddCentroids([][2]float64{{10, 0}, {10, 0}, {10, 0}, {10, 0}, {60, 0}})
+	normalized := grid.Normalize()
+	if normalized.At(0, 0) != 1 || normalized.At(2, 0) != 0.25 || normalized.Max() != 1 {
+		t.Fatalf("Normalize: got %v", normalized.Cells)
+	}
+	if empty := mustDensityGrid(t, 2, 2).Normalize(); empty.Max() != 0 {
+		t.Fatalf("Normalize of an empty grid: got %v", empty.Cells)
+	}
+}
+
+func TestDensityGrid_EncodePNG(t *testing.T) {
+	grid := mustDensityGrid(t, 8, 4)
+	grid.AddCentroids([][2]float64{{10, 10}, {10, 10}, {90, 90}})
+
+	var buf bytes.Buffer
+	if err := grid.EncodePNG(&buf); err != nil {
+		t.Fatalf("EncodePNG returned error: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode returned error: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 8 || bounds.Dy() != 4 {
+		t.Fatalf("image size: got %v", bounds)
+	}
+	if _, _, _, a := img.At(3, 2).RGBA(); a != 0 {
+		t.Fatalf("empty cell should be transparent, alpha %d", a)
+	}
+	if r, g, b, a := img.At(0, 0).RGBA(); r != 0xffff || g != 0 || b != 0 || a != 0xffff {
+		t.Fatalf("hottest cell should be opaque red, got %d %d %d %d", r, g, b, a)
+	}
+	if _, _, b, a := img.At(7, 3).RGBA(); a == 0 || a == 0xffff || b == 0xffff {
+		t.Fatalf("half density cell should be partially transparent and not blue, got b=%d a=%d", b, a)
+	}
+}
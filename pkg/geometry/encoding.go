@@ -0,0 +1,77 @@
+package geometry
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// toFloat converts the numeric types produced by JSON and BSON decoding to float64.
+func toFloat(value any) (float64, bool) {
+	switch cast := value.(type) {
+	case float64:
+		return cast, true
+	case float32:
+		return float64(cast), true
+	case int:
+		return float64(cast), true
+	case int32:
+		return float64(cast), true
+	case int64:
+		return float64(cast), true
+	case json.Number:
+		f, err := cast.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// unmarshalJSONFloats decodes a JSON array of exactly n numbers.
+func unmarshalJSONFloats(data []byte, n int, name string) ([]float64, error) {
+	var values []float64
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", name, err)
+	}
+	if len(values) != n {
+		return nil, fmt.Errorf("cannot decode %s: expected %d numbers, got %d", name, n, len(values))
+	}
+	return values, nil
+}
+
+// marshalBSONFloats encodes values as a BSON array of doubles.
+func marshalBSONFloats(values []float64) (byte, []byte, error) {
+	typ, data, err := bson.MarshalValue(values)
+	return byte(typ), data, err
+}
+
+// unmarshalBSONFloats decodes a BSON array of exactly n numbers.
+func unmarshalBSONFloats(typ byte, data []byte, n int, name string) ([]float64, error) {
+	raw := bson.RawValue{Type: bson.Type(typ), Value: data}
+	array, ok := raw.ArrayOK()
+	if !ok {
+		return nil, fmt.Errorf("cannot decode BSON %s into %s", bson.Type(typ), name)
+	}
+	elements, err := array.Values()
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s: %v", name, err)
+	}
+	if len(elements) != n {
+		return nil, fmt.Errorf("cannot decode %s: expected %d numbers, got %d", name, n, len(elements))
+	}
+	values := make([]float64, n)
+	for i, element := range elements {
+		switch element.Type {
+		case bson.TypeDouble:
+			values[i] = element.Double()
+		case bson.TypeInt32:
+			values[i] = float64(element.Int32())
+		case bson.TypeInt64:
+			values[i] = float64(element.Int64())
+		default:
+			return nil, fmt.Errorf("cannot decode %s: element %d is %s, not a number", name, i, element.Type)
+		}
+	}
+	return values, nil
+}
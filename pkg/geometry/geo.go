@@ -0,0 +1,374 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// EarthRadius is the mean radius of the Earth in meters, as used by Haversine.
+	EarthRadius = 6371008.8
+
+	// WGS84 ellipsoid parameters, as used by Vincenty.
+	wgs84SemiMajorAxis = 6378137.0
+	wgs84Flattening    = 1 / 298.257223563
+)
+
+// LatLng is a geographic position in degrees.
+type LatLng struct {
+	Lat float64 `json:"lat" bson:"lat"`
+	Lng float64 `json:"lng" bson:"lng"`
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
+
+func degrees(radians float64) float64 {
+	return radians * 180 / math.Pi
+}
+
+// normalizeLongitude maps a longitude into [-180, 180).
+func normalizeLongitude(lng float64) float64 {
+	return math.Mod(math.Mod(lng+180, 360)+360, 360) - 180
+}
+
+// HaversineDistance returns the great-circle distance between a and b in meters on
+// a spherical Earth. It is accurate to about 0.5%.
+func HaversineDistance(a, b LatLng) float64 {
+	dLat := radians(b.Lat - a.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// VincentyDistance returns the distance between a and b in meters on the WGS84
+// ellipsoid, accurate to within a millimeter. It fails for nearly antipodal points,
+// where the method does not converge; fall back to HaversineDistance there.
+func VincentyDistance(a, b LatLng) (float64, error) {
+	f := wgs84Flattening
+	semiMinor := (1 - f) * wgs84SemiMajorAxis
+	L := radians(b.Lng - a.Lng)
+	U1 := math.Atan((1 - f) * math.Tan(radians(a.Lat)))
+	U2 := math.Atan((1 - f) * math.Tan(radians(b.Lat)))
+	sinU1, cosU1 := math.Sincos(U1)
+	sinU2, cosU2 := math.Sincos(U2)
+
+	lambda := L
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0, nil
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cosSqAlpha != 0 {
+			// Both points on the equator leave cos2SigmaM at 0.
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		C := f / 16 * cosSqAlpha * (4 + f*(4-3*cosSqAlpha))
+		previous := lambda
+		lambda = L + (1-C)*f*sinAlpha*(sigma+C*sinSigma*(cos2SigmaM+C*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) < 1e-12 {
+			uSq := cosSqAlpha * (wgs84SemiMajorAxis*wgs84SemiMajorAxis - semiMinor*semiMinor) / (semiMinor * semiMinor)
+			A := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+			B := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+			deltaSigma := B * sinSigma * (cos2SigmaM + B/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				B/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+			return semiMinor * A * (sigma - deltaSigma), nil
+		}
+	}
+	return 0, fmt.Errorf("vincenty distance between %v and %v did not converge", a, b)
+}
+
+// InitialBearing returns the direction to travel from a to reach b along a great
+// circle, in degrees clockwise from north in [0, 360).
+func InitialBearing(a, b LatLng) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	y := math.Sin(dLng) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLng)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// Destination returns the position reached by travelling distance meters from
+// start along a great circle with the given initial bearing in degrees.
+func Destination(start LatLng, bearing, distance float64) LatLng {
+	lat1, lng1 := radians(start.Lat), radians(start.Lng)
+	angular := distance / EarthRadius
+	theta := radians(bearing)
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) + math.Cos(lat1)*math.Sin(angular)*math.Cos(theta))
+	lng2 := lng1 + math.Atan2(math.Sin(theta)*math.Sin(angular)*math.Cos(lat1), math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+	return LatLng{Lat: degrees(lat2), Lng: normalizeLongitude(degrees(lng2))}
+}
+
+// GeoBounds is a latitude/longitude rectangle, e.g. a map viewport. When it crosses
+// the antimeridian, SouthWest.Lng is greater than NorthEast.Lng.
+type GeoBounds struct {
+	SouthWest LatLng `json:"southWest" bson:"southWest"`
+	NorthEast LatLng `json:"northEast" bson:"northEast"`
+}
+
+// Contains reports whether p lies inside the bounds, borders included.
+func (b GeoBounds) Contains(p LatLng) bool {
+	if p.Lat < b.SouthWest.Lat || p.Lat > b.NorthEast.Lat {
+		return false
+	}
+	if b.SouthWest.Lng <= b.NorthEast.Lng {
+		return p.Lng >= b.SouthWest.Lng && p.Lng <= b.NorthEast.Lng
+	}
+	return p.Lng >= b.SouthWest.Lng || p.Lng <= b.NorthEast.Lng
+}
+
+// Center returns the middle of the bounds.
+func (b GeoBounds) Center() LatLng {
+	width := b.NorthEast.Lng - b.SouthWest.Lng
+	if width < 0 {
+		width += 360
+	}
+	return LatLng{
+		Lat: (b.SouthWest.Lat + b.NorthEast.Lat) / 2,
+		Lng: normalizeLongitude(b.SouthWest.Lng + width/2),
+	}
+}
+
+// BoundsAround returns the smallest bounds containing the circle of radius meters
+// around center, e.g. to prefilter a "within 500 m" query. Near a pole the bounds
+// span all longitudes.
+func BoundsAround(center LatLng, radius float64) GeoBounds {
+	angular := degrees(radius / EarthRadius)
+	south, north := center.Lat-angular, center.Lat+angular
+	if south <= -90 || north >= 90 {
+		return GeoBounds{
+			SouthWest: LatLng{Lat: math.Max(south, -90), Lng: -180},
+			NorthEast: LatLng{Lat: math.Min(north, 90), Lng: 180},
+		}
+	}
+	// The widest longitude span of the circle, at the latitude where its edge is
+	// tangent to a meridian.
+	dLng := degrees(math.Asin(math.Sin(radius/EarthRadius) / math.Cos(radians(center.Lat))))
+	return GeoBounds{
+		SouthWest: LatLng{Lat: south, Lng: normalizeLongitude(center.Lng - dLng)},
+		NorthEast: LatLng{Lat: north, Lng: normalizeLongitude(center.Lng + dLng)},
+	}
+}
+
+// WithinRadius returns the indices of the positions within radius meters of center,
+// by Haversine distance.
+func WithinRadius(center LatLng, positions []LatLng, radius float64) []int {
+	bounds := BoundsAround(center, radius)
+	var found []int
+	for i, p := range positions {
+		if bounds.Contains(p) && HaversineDistance(center, p) <= radius {
+			found = append(found, i)
+		}
+	}
+	return found
+}
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashEncode returns the geohash of p with precision characters, between 1
+// and 12.
+func GeohashEncode(p LatLng, precision int) string {
+	precision = max(1, min(precision, 12))
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	var hash strings.Builder
+	even, bit, index := true, 0, 0
+	for hash.Len() < precision {
+		r, value := &latRange, p.Lat
+		if even {
+			r, value = &lngRange, p.Lng
+		}
+		mid := (r[0] + r[1]) / 2
+		index <<= 1
+		if value >= mid {
+			index |= 1
+			r[0] = mid
+		} else {
+			r[1] = mid
+		}
+		even = !even
+		if bit++; bit == 5 {
+			hash.WriteByte(geohashAlphabet[index])
+			bit, index = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// GeohashDecode returns the center and the bounds of the cell of a geohash.
+func GeohashDecode(hash string) (LatLng, GeoBounds, error) {
+	if hash == "" {
+		return LatLng{}, GeoBounds{}, fmt.Errorf("empty geohash")
+	}
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+	even := true
+	for i, c := range strings.ToLower(hash) {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return LatLng{}, GeoBounds{}, fmt.Errorf("invalid geohash character %q at position %d", c, i)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			r := &latRange
+			if even {
+				r = &lngRange
+			}
+			mid := (r[0] + r[1]) / 2
+			if index>>bit&1 == 1 {
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+	}
+	bounds := GeoBounds{
+		SouthWest: LatLng{Lat: latRange[0], Lng: lngRange[0]},
+		NorthEast: LatLng{Lat: latRange[1], Lng: lngRange[1]},
+	}
+	center := LatLng{Lat: (latRange[0] + latRange[1]) / 2, Lng: (lngRange[0] + lngRange[1]) / 2}
+	return center, bounds, nil
+}
+
+// GeohashNeighbours returns the eight cells around a geohash, in the order north,
+// north-east, east, south-east, south, south-west, west and north-west. Cells wrap
+// around the antimeridian; beyond a pole the cell itself is repeated.
+func GeohashNeighbours(hash string) ([8]string, error) {
+	center, bounds, err := GeohashDecode(hash)
+	if err != nil {
+		return [8]string{}, err
+	}
+	height := bounds.NorthEast.Lat - bounds.SouthWest.Lat
+	width := bounds.NorthEast.Lng - bounds.SouthWest.Lng
+	offsets := [8][2]float64{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+	var neighbours [8]string
+	for i, offset := range offsets {
+		lat := center.Lat + offset[0]*height
+		if lat > 90 || lat < -90 {
+			lat = center.Lat
+		}
+		lng := normalizeLongitude(center.Lng + offset[1]*width)
+		neighbours[i] = GeohashEncode(LatLng{Lat: lat, Lng: lng}, len(hash))
+	}
+	return neighbours, nil
+}
+
+// GeoPoint is a GeoJSON Point, stored as {type: "Point", coordinates: [lng, lat]}
+// so it can be indexed by a MongoDB 2dsphere index.
+type GeoPoint struct {
+	Type        string     `json:"type" bson:"type"`
+	Coordinates [2]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// NewGeoPoint returns the GeoJSON Point of p.
+func NewGeoPoint(p LatLng) GeoPoint {
+	return GeoPoint{Type: "Point", Coordinates: [2]float64{p.Lng, p.Lat}}
+}
+
+// LatLng returns the position of the point.
+func (g GeoPoint) LatLng() LatLng {
+	return LatLng{Lat: g.Coordinates[1], Lng: g.Coordinates[0]}
+}
+
+// Validate checks that the point is a GeoJSON Point within the valid coordinate
+// ranges.
+func (g GeoPoint) Validate() error {
+	if g.Type != "Point" {
+		return fmt.Errorf("invalid GeoJSON Point type %q", g.Type)
+	}
+	return validatePosition(g.Coordinates)
+}
+
+// GeoPolygon is a GeoJSON Polygon, stored as {type: "Polygon", coordinates: rings}
+// so it can be indexed by a MongoDB 2dsphere index. The first ring is the outer
+// boundary and any others are holes; every ring is closed, with its first position
+// repeated at the end.
+type GeoPolygon struct {
+	Type        string         `json:"type" bson:"type"`
+	Coordinates [][][2]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// NewGeoPolygon returns the GeoJSON Polygon with outer boundary ring, closing the
+// ring when needed and orienting it counterclockwise as RFC 7946 recommends.
+func NewGeoPolygon(ring []LatLng) GeoPolygon {
+	positions := make([][2]float64, 0, len(ring)+1)
+	for _, p := range ring {
+		positions = append(positions, [2]float64{p.Lng, p.Lat})
+	}
+	return GeoPolygon{Type: "Polygon", Coordinates: [][][2]float64{geoJSONRing(positions)}}
+}
+
+// geoJSONRing closes a ring of [x, y] positions by repeating the first one at the
+// end when needed, and orients it counterclockwise with x pointing right and y up.
+func geoJSONRing(positions [][2]float64) [][2]float64 {
+	if len(positions) > 0 && positions[0] != positions[len(positions)-1] {
+		positions = append(positions, positions[0])
+	}
+	// With longitude as x and latitude as y, counterclockwise has a positive area.
+	area := 0.0
+	for i := 1; i < len(positions); i++ {
+		area += positions[i-1][0]*positions[i][1] - positions[i][0]*positions[i-1][1]
+	}
+	if area < 0 {
+		for i, j := 0, len(positions)-1; i < j; i, j = i+1, j-1 {
+			positions[i], positions[j] = positions[j], positions[i]
+		}
+	}
+	return positions
+}
+
+// Ring returns the outer boundary of the polygon, without the closing position.
+func (g GeoPolygon) Ring() []LatLng {
+	if len(g.Coordinates) == 0 || len(g.Coordinates[0]) == 0 {
+		return nil
+	}
+	outer := g.Coordinates[0]
+	ring := make([]LatLng, 0, len(outer)-1)
+	for _, c := range outer[:len(outer)-1] {
+		ring = append(ring, LatLng{Lat: c[1], Lng: c[0]})
+	}
+	return ring
+}
+
+// Validate checks that the polygon is a GeoJSON Polygon MongoDB accepts: at least
+// one ring, rings closed with at least four positions, and valid coordinates.
+func (g GeoPolygon) Validate() error {
+	if g.Type != "Polygon" {
+		return fmt.Errorf("invalid GeoJSON Polygon type %q", g.Type)
+	}
+	if len(g.Coordinates) == 0 {
+		return fmt.Errorf("GeoJSON Polygon has no rings")
+	}
+	for i, ring := range g.Coordinates {
+		if len(ring) < 4 {
+			return fmt.Errorf("GeoJSON Polygon ring %d has %d positions, need at least 4", i, len(ring))
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("GeoJSON Polygon ring %d is not closed", i)
+		}
+		for _, position := range ring {
+			if err := validatePosition(position); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validatePosition(position [2]float64) error {
+	if position[0] < -180 || position[0] > 180 || math.IsNaN(position[0]) {
+		return fmt.Errorf("invalid longitude %v", position[0])
+	}
+	if position[1] < -90 || position[1] > 90 || math.IsNaN(position[1]) {
+		return fmt.Errorf("invalid latitude %v", position[1])
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var (
+	brussels = LatLng{Lat: 50.8503, Lng: 4.3517}
+	paris    = LatLng{Lat: 48.8566, Lng: 2.3522}
+	// Flinders Peak and Buninyong, the reference example of Vincenty's paper.
+	flindersPeak = LatLng{Lat: -37.95103342, Lng: 144.42486789}
+	buninyong    = LatLng{Lat: -37.65282114, Lng: 143.92649554}
+)
+
+func TestHaversineDistance(t *testing.T) {
+	if got := HaversineDistance(brussels, paris); math.Abs(got-264000) > 1000 {
+		t.Fatalf("HaversineDistance(Brussels, Paris): got %v, want about 264 km", got)
+	}
+	if got := HaversineDistance(brussels, brussels); got != 0 {
+		t.Fatalf("HaversineDistance to itself: got %v", got)
+	}
+	// Half the circumference between antipodes.
+	if got := HaversineDistance(LatLng{0, 0}, LatLng{0, 180}); math.Abs(got-math.Pi*EarthRadius) > 1e-6 {
+		t.Fatalf("HaversineDistance antipodes: got %v", got)
+	}
+}
+
+func TestVincentyDistance(t *testing.T) {
+	got, err := VincentyDistance(flindersPeak, buninyong)
+	if err != nil {
+		t.Fatalf("VincentyDistance returned error: %v", err)
+	}
+	if math.Abs(got-54972.271) > 0.001 {
+		t.Fatalf("VincentyDistance: got %.4f, want 54972.271", got)
+	}
+	if got, _ := VincentyDistance(LatLng{0, 0}, LatLng{0, 1}); math.Abs(got-111319.491) > 0.001 {
+		t.Fatalf("VincentyDistance along the equator: got %.4f, want 111319.491", got)
+	}
+	if got, _ := VincentyDistance(brussels, brussels); got != 0 {
+		t.Fatalf("VincentyDistance to itself: got %v", got)
+	}
+	if _, err := VincentyDistance(LatLng{0, 0}, LatLng{0.5, 179.7}); err == nil {
+		t.Fatalf("expected error for nearly antipodal points")
+	}
+}
+
+func TestInitialBearingAndDestination(t *testing.T) {
+	// The bearing is spherical, so it differs slightly from the ellipsoidal azimuth
+	// of 306.868 degrees in Vincenty's example.
+	if got := InitialBearing(flindersPeak, buninyong); math.Abs(got-306.868) > 0.2 {
+		t.Fatalf("InitialBearing: got %v, want about 306.868", got)
+	}
+	tests := []struct {
+		to   LatLng
+		want float64
+	}{
+		{LatLng{1, 0}, 0}, {LatLng{0, 1}, 90}, {LatLng{-1, 0}, 180}, {LatLng{0, -1}, 270},
+	}
+	for _, tt := range tests {
+		if got := InitialBearing(LatLng{}, tt.to); math.Abs(got-tt.want) > 1e-9 {
+			t.Fatalf("InitialBearing to %v: got %v, want %v", tt.to, got, tt.want)
+		}
+	}
+
+	bearing := InitialBearing(brussels, paris)
+	destination := Destination(brussels, bearing, HaversineDistance(brussels, paris))
+	if HaversineDistance(destination, paris) > 0.01 {
+		t.Fatalf("Destination: got %v, want %v", destination, paris)
+	}
+	if got := Destination(LatLng{0, 179.5}, 90, 111195); math.Abs(got.Lng+179.5) > 0.01 {
+		t.Fatalf("Destination across the antimeridian: got %v", got)
+	}
+}
+
+func TestBoundsAroundAndWithinRadius(t *testing.T) {
+	bounds := BoundsAround(brussels, 500)
+	for _, bearing := range []float64{0, 45, 90, 135, 180, 225, 270, 315} {
+		if p := Destination(brussels, bearing, 499); !bounds.Contains(p) {
+			t.Fatalf("BoundsAround does not contain %v at bearing %v", p, bearing)
+		}
+		if p := Destination(brussels, bearing, 520); bearing == 0 && bounds.Contains(p) {
+			t.Fatalf("BoundsAround too large: contains %v", p)
+		}
+	}
+	if center := bounds.Center(); HaversineDistance(center, brussels) > 1e-6 {
+		t.Fatalf("Center: got %v", center)
+	}
+
+	cameras := []LatLng{
+		Destination(brussels, 10, 100),
+		Destination(brussels, 200, 499),
+		Destination(brussels, 90, 501),
+		paris,
+	}
+	if got := WithinRadius(brussels, cameras, 500); !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Fatalf("WithinRadius: got %v, want [0 1]", got)
+	}
+
+	antimeridian := BoundsAround(LatLng{0, 179.999}, 1000)
+	if antimeridian.SouthWest.Lng <= antimeridian.NorthEast.Lng || !antimeridian.Contains(LatLng{0, -179.999}) {
+		t.Fatalf("BoundsAround across the antimeridian: got %+v", antimeridian)
+	}
+	if pole := BoundsAround(LatLng{89.999, 0}, 1000); pole.SouthWest.Lng != -180 || pole.NorthEast.Lat != 90 {
+		t.Fatalf("BoundsAround near the pole: got %+v", pole)
+	}
+}
+
+func TestGeohash(t *testing.T) {
+	p := LatLng{Lat: 57.64911, Lng: 10.40744}
+	if got := GeohashEncode(p, 11); got != "u4pruydqqvj" {
+		t.Fatalf("GeohashEncode: got %q, want u4pruydqqvj", got)
+	}
+	if got := GeohashEncode(p, 0); got != "u" {
+		t.Fatalf("GeohashEncode with precision 0: got %q", got)
+	}
+
+	center, bounds, err := GeohashDecode("u4pruydqqvj")
+	if err != nil {
+		t.Fatalf("GeohashDecode returned error: %v", err)
+	}
+	if !bounds.Contains(p) || math.Abs(center.Lat-p.Lat) > 1e-5 || math.Abs(center.Lng-p.Lng) > 1e-5 {
+		t.Fatalf("GeohashDecode: got %v in %+v", center, bounds)
+	}
+	if _, _, err := GeohashDecode("u4pa"); err == nil {
+		t.Fatalf("expected error for invalid character")
+	}
+	if _, _, err := GeohashDecode(""); err == nil {
+		t.Fatalf("expected error for empty geohash")
+	}
+}
+
+func TestGeohashNeighbours(t *testing.T) {
+	got, err := GeohashNeighbours("ezs42")
+	if err != nil {
+		t.Fatalf("GeohashNeighbours returned error: %v", err)
+	}
+	want := [8]string{"ezs48", "ezs49", "ezs43", "ezs41", "ezs40", "ezefp", "ezefr", "ezefx"}
+	if got != want {
+		t.Fatalf("GeohashNeighbours: got %v, want %v", got, want)
+	}
+
+	// Cells on the antimeridian wrap around.
+	edge := GeohashEncode(LatLng{Lat: 10, Lng: 179.99}, 5)
+	neighbours, _ := GeohashNeighbours(edge)
+	if east, _, _ := GeohashDecode(neighbours[2]); east.Lng > -179 {
+		t.Fatalf("east neighbour of %s is not across the antimeridian: %v", edge, east)
+	}
+	if _, err := GeohashNeighbours("!"); err == nil {
+		t.Fatalf("expected error for invalid geohash")
+	}
+}
+
+func TestGeoPoint(t *testing.T) {
+	point := NewGeoPoint(brussels)
+	if point.Coordinates != [2]float64{4.3517, 50.8503} || point.LatLng() != brussels {
+		t.Fatalf("NewGeoPoint: got %+v", point)
+	}
+	data, err := json.Marshal(point)
+	if err != nil || string(data) != `{"type":"Point","coordinates":[4.3517,50.8503]}` {
+		t.Fatalf("json.Marshal: got %s, %v", data, err)
+	}
+
+	document := bson.M{"name": "camera", "location": point}
+	raw, err := bson.Marshal(document)
+	if err != nil {
+		t.Fatalf("bson.Marshal returned error: %v", err)
+	}
+	var decoded struct {
+		Location GeoPoint `bson:"location"`
+	}
+	if err := bson.Unmarshal(raw, &decoded); err != nil || decoded.Location != point {
+		t.Fatalf("bson round trip: got %+v, %v", decoded.Location, err)
+	}
+	typ, err := bson.Raw(raw).LookupErr("location", "type")
+	if err != nil || typ.StringValue() != "Point" {
+		t.Fatalf("BSON location.type: got %v, %v", typ, err)
+	}
+
+	if err := point.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if err := (GeoPoint{Type: "Point", Coordinates: [2]float64{0, 91}}).Validate(); err == nil {
+		t.Fatalf("expected error for invalid latitude")
+	}
+	if err := (GeoPoint{Type: "point"}).Validate(); err == nil {
+		t.Fatalf("expected error for invalid type")
+	}
+}
+
+func TestGeoPolygon(t *testing.T) {
+	// A clockwise ring, without the closing position.
+	ring := []LatLng{{Lat: 51, Lng: 4}, {Lat: 51, Lng: 5}, {Lat: 50, Lng: 5}, {Lat: 50, Lng: 4}}
+	polygon := NewGeoPolygon(ring)
+	want := [][][2]float64{{{4, 51}, {4, 50}, {5, 50}, {5, 51}, {4, 51}}}
+	if !reflect.DeepEqual(polygon.Coordinates, want) {
+		t.Fatalf("NewGeoPolygon: got %v, want %v", polygon.Coordinates, want)
+	}
+	if err := polygon.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if got := polygon.Ring(); len(got) != 4 || got[0] != ring[0] {
+		t.Fatalf("Ring: got %v", got)
+	}
+
+	raw, err := bson.Marshal(bson.M{"area": polygon})
+	if err != nil {
+		t.Fatalf("bson.Marshal returned error: %v", err)
+	}
+	var decoded struct {
+		Area GeoPolygon `bson:"area"`
+	}
+	if err := bson.Unmarshal(raw, &decoded); err != nil || !reflect.DeepEqual(decoded.Area, polygon) {
+		t.Fatalf("bson round trip: got %+v, %v", decoded.Area, err)
+	}
+
+	invalid := []GeoPolygon{
+		{Type: "Polygon"},
+		{Type: "Point", Coordinates: want},
+		{Type: "Polygon", Coordinates: [][][2]float64{{{4, 51}, {4, 50}, {4, 51}}}},
+		{Type: "Polygon", Coordinates: [][][2]float64{{{4, 51}, {4, 50}, {5, 50}, {5, 51}}}},
+		{Type: "Polygon", Coordinates: [][][2]float64{{{4, 51}, {4, 50}, {185, 50}, {4, 51}}}},
+	}
+	for i, p := range invalid {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("invalid polygon %d: expected error", i)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package geometry
+
+// GeoJSONGeometry is a GeoJSON geometry. Coordinates holds a position, a list of
+// positions or a list of rings, depending on Type.
+type GeoJSONGeometry struct {
+	Type        string `json:"type" bson:"type"`
+	Coordinates any    `json:"coordinates" bson:"coordinates"`
+}
+
+// Feature is a GeoJSON Feature.
+type Feature struct {
+	Type       string          `json:"type" bson:"type"`
+	Geometry   GeoJSONGeometry `json:"geometry" bson:"geometry"`
+	Properties map[string]any  `json:"properties" bson:"properties"`
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection of trajectories, boxes and
+// polygons, e.g. for a map layer. Coordinates are written as given, in [x, y]
+// order: pass [lng, lat] pairs for geographic maps, or frame or floorplan
+// coordinates (e.g. mapped with a Homography) for image based maps.
+type FeatureCollection struct {
+	Type     string    `json:"type" bson:"type"`
+	Features []Feature `json:"features" bson:"features"`
+}
+
+// NewFeatureCollection returns an empty feature collection.
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{Type: "FeatureCollection", Features: []Feature{}}
+}
+
+func (fc *FeatureCollection) add(geometryType string, coordinates any, properties map[string]any) {
+	if properties == nil {
+		properties = map[string]any{}
+	}
+	fc.Features = append(fc.Features, Feature{
+		Type:       "Feature",
+		Geometry:   GeoJSONGeometry{Type: geometryType, Coordinates: coordinates},
+		Properties: properties,
+	})
+}
+
+// closedRing returns the positions of a polygon as a closed, counterclockwise ring,
+// like NewGeoPolygon does.
+func closedRing(p Polygon) [][2]float64 {
+	ring := make([][2]float64, 0, len(p)+1)
+	for _, v := range p {
+		ring = append(ring, v.Array())
+	}
+	return geoJSONRing(ring)
+}
+
+// AddPoint adds a Point feature.
+func (fc *FeatureCollection) AddPoint(p Point, properties map[string]any) {
+	fc.add("Point", p.Array(), properties)
+}
+
+// AddTrajectory adds a path through centroids as a LineString feature, or as a
+// Point feature when it has a single point. Empty paths are skipped, as GeoJSON
+// has no empty LineString.
+func (fc *FeatureCollection) AddTrajectory(centroids [][2]float64, properties map[string]any) {
+	switch len(centroids) {
+	case 0:
+		return
+	case 1:
+		fc.add("Point", centroids[0], properties)
+		return
+	}
+	fc.add("LineString", append([][2]float64{}, centroids...), properties)
+}
+
+// AddBox adds a box as a Polygon feature.
+func (fc *FeatureCollection) AddBox(b BBox, properties map[string]any) {
+	corners := Polygon{{b.X1, b.Y1}, {b.X2, b.Y1}, {b.X2, b.Y2}, {b.X1, b.Y2}}
+	fc.add("Polygon", [][][2]float64{closedRing(corners)}, properties)
+}
+
+// AddPolygon adds a polygon, e.g. a zone, as a Polygon feature. Polygons with fewer
+// than three vertices are skipped.
+func (fc *FeatureCollection) AddPolygon(p Polygon, properties map[string]any) {
+	if len(p) < 3 {
+		return
+	}
+	fc.add("Polygon", [][][2]float64{closedRing(p)}, properties)
+}
@@ -0,0 +1,64 @@
+package geometry
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFeatureCollection_Golden(t *testing.T) {
+	fc := NewFeatureCollection()
+	fc.AddTrajectory(goldenPath, map[string]any{"id": "track-1", "class": "person"})
+	fc.AddTrajectory(goldenPath[:1], nil)
+	fc.AddBox(NewBBoxXYWH(85, 12, 10, 16), map[string]any{"score": 0.9})
+	fc.AddPolygon(NewPolygon([][2]float64{{50, 10}, {95, 10}, {95, 45}, {50, 45}}), map[string]any{"name": "entrance"})
+	fc.AddPoint(Point{X: 4.3517, Y: 50.8503}, map[string]any{"camera": "front door"})
+
+	data, err := json.MarshalIndent(fc, "", "  ")
+	if err != nil {
+		t.Fatalf("json.MarshalIndent returned error: %v", err)
+	}
+	assertGolden(t, "features.geojson", append(data, '\n'))
+}
+
+func TestFeatureCollection_Empty(t *testing.T) {
+	data, err := json.Marshal(NewFeatureCollection())
+	if err != nil || string(data) != `{"type":"FeatureCollection","features":[]}` {
+		t.Fatalf("json.Marshal: got %s, %v", data, err)
+	}
+}
+
+func TestFeatureCollection_RingsAreCounterclockwise(t *testing.T) {
+	fc := NewFeatureCollection()
+	// Clockwise with y up, the opposite of what NewGeoPolygon produces.
+	clockwise := NewPolygon([][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}})
+	fc.AddPolygon(clockwise, nil)
+	fc.AddBox(BBox{X1: 10, Y1: 10, X2: 0, Y2: 0}, nil)
+
+	want := NewGeoPolygon([]LatLng{{Lat: 0, Lng: 0}, {Lat: 10, Lng: 0}, {Lat: 10, Lng: 10}, {Lat: 0, Lng: 10}}).Coordinates
+	for i, feature := range fc.Features {
+		rings, ok := feature.Geometry.Coordinates.([][][2]float64)
+		if !ok || len(rings) != 1 {
+			t.Fatalf("feature %d: got coordinates %v", i, feature.Geometry.Coordinates)
+		}
+		if area := NewPolygon(rings[0]).SignedArea(); area <= 0 {
+			t.Fatalf("feature %d: ring %v is not counterclockwise", i, rings[0])
+		}
+		if err := (GeoPolygon{Type: "Polygon", Coordinates: rings}).Validate(); err != nil {
+			t.Fatalf("feature %d: %v", i, err)
+		}
+	}
+	if got := fc.Features[0].Geometry.Coordinates.([][][2]float64); !reflect.DeepEqual(got, want) {
+		t.Fatalf("AddPolygon: got %v, want the NewGeoPolygon ring %v", got, want)
+	}
+}
+
+func TestFeatureCollection_SkipsEmptyGeometries(t *testing.T) {
+	fc := NewFeatureCollection()
+	fc.AddTrajectory(nil, nil)
+	fc.AddPolygon(nil, nil)
+	fc.AddPolygon(NewPolygon([][2]float64{{0, 0}, {1, 1}}), nil)
+	if len(fc.Features) != 0 {
+		t.Fatalf("expected no features, got %+v", fc.Features)
+	}
+}
@@ -0,0 +1,238 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Homography is a 3x3 projective transform, e.g. from camera pixels to a site
+// floorplan. A point (x, y) maps to (u/w, v/w) with (u, v, w) = H * (x, y, 1).
+type Homography [3][3]float64
+
+// NewHomography estimates the homography mapping src onto dst from four or more
+// point correspondences, with the normalized Direct Linear Transform. With more
+// than four correspondences it is a least squares fit. It fails when three or more
+// points are collinear in a way that leaves the mapping undetermined.
+func NewHomography(src, dst []Point) (Homography, error) {
+	if len(src) != len(dst) {
+		return Homography{}, fmt.Errorf("got %d source and %d destination points", len(src), len(dst))
+	}
+	if len(src) < 4 {
+		return Homography{}, fmt.Errorf("need at least 4 point correspondences, got %d", len(src))
+	}
+	srcNorm, srcPoints, err := normalizePoints(src)
+	if err != nil {
+		return Homography{}, err
+	}
+	dstNorm, dstPoints, err := normalizePoints(dst)
+	if err != nil {
+		return Homography{}, err
+	}
+
+	// Every correspondence gives two rows of A in A*h = 0. The solution is the
+	// eigenvector of A^T*A with the smallest eigenvalue.
+	var ata [9][9]float64
+	for i := range srcPoints {
+		x, y := srcPoints[i].X, srcPoints[i].Y
+		u, v := dstPoints[i].X, dstPoints[i].Y
+		rows := [2][9]float64{
+			{-x, -y, -1, 0, 0, 0, u * x, u * y, u},
+			{0, 0, 0, -x, -y, -1, v * x, v * y, v},
+		}
+		for _, row := range rows {
+			for j := 0; j < 9; j++ {
+				for k := 0; k < 9; k++ {
+					ata[j][k] += row[j] * row[k]
+				}
+			}
+		}
+	}
+	values, vectors := symmetricEigen(ata)
+	smallest, second, largest := 0, -1, 0
+	for i := range values {
+		if values[i] < values[smallest] {
+			smallest = i
+		}
+		if values[i] > values[largest] {
+			largest = i
+		}
+	}
+	for i := range values {
+		if i != smallest && (second < 0 || values[i] < values[second]) {
+			second = i
+		}
+	}
+	if values[second] <= 1e-10*values[largest] {
+		return Homography{}, fmt.Errorf("point correspondences are degenerate")
+	}
+
+	var normalized Homography
+	for i := 0; i < 9; i++ {
+		normalized[i/3][i%3] = vectors[i][smallest]
+	}
+	dstInverse, err := dstNorm.Inverse()
+	if err != nil {
+		return Homography{}, err
+	}
+	h := dstInverse.multiply(normalized).multiply(srcNorm)
+	if h[2][2] != 0 {
+		h = h.scale(1 / h[2][2])
+	}
+	if h.determinant() == 0 {
+		return Homography{}, fmt.Errorf("point correspondences are degenerate")
+	}
+	return h, nil
+}
+
+// normalizePoints returns the similarity moving the points' centroid to the origin
+// with a mean distance of sqrt(2), and the transformed points.
+func normalizePoints(points []Point) (Homography, []Point, error) {
+	var center Point
+	for _, p := range points {
+		center = center.Add(p)
+	}
+	center = Point{X: center.X / float64(len(points)), Y: center.Y / float64(len(points))}
+	meanDistance := 0.0
+	for _, p := range points {
+		meanDistance += p.Distance(center)
+	}
+	meanDistance /= float64(len(points))
+	if meanDistance == 0 || math.IsNaN(meanDistance) || math.IsInf(meanDistance, 0) {
+		return Homography{}, nil, fmt.Errorf("point correspondences are degenerate")
+	}
+	s := math.Sqrt2 / meanDistance
+	h := Homography{{s, 0, -s * center.X}, {0, s, -s * center.Y}, {0, 0, 1}}
+	normalized := make([]Point, len(points))
+	for i, p := range points {
+		normalized[i] = h.Apply(p)
+	}
+	return h, normalized, nil
+}
+
+// symmetricEigen returns the eigenvalues of a symmetric matrix and its eigenvectors
+// as columns, using the cyclic Jacobi method.
+func symmetricEigen(m [9][9]float64) ([9]float64, [9][9]float64) {
+	var vectors [9][9]float64
+	for i := range vectors {
+		vectors[i][i] = 1
+	}
+	for sweep := 0; sweep < 100; sweep++ {
+		offDiagonal := 0.0
+		for p := 0; p < 9; p++ {
+			for q := p + 1; q < 9; q++ {
+				offDiagonal += m[p][q] * m[p][q]
+			}
+		}
+		if offDiagonal < 1e-30 {
+			break
+		}
+		for p := 0; p < 9; p++ {
+			for q := p + 1; q < 9; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < 9; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < 9; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < 9; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p], vectors[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	var values [9]float64
+	for i := range values {
+		values[i] = m[i][i]
+	}
+	return values, vectors
+}
+
+// Apply maps a point. Points on the horizon of the homography map to infinity.
+func (h Homography) Apply(p Point) Point {
+	w := h[2][0]*p.X + h[2][1]*p.Y + h[2][2]
+	return Point{
+		X: (h[0][0]*p.X + h[0][1]*p.Y + h[0][2]) / w,
+		Y: (h[1][0]*p.X + h[1][1]*p.Y + h[1][2]) / w,
+	}
+}
+
+// ApplyCentroids maps centroids as returned by BuildCentroids.
+func (h Homography) ApplyCentroids(centroids [][2]float64) [][2]float64 {
+	mapped := make([][2]float64, len(centroids))
+	for i, c := range centroids {
+		mapped[i] = h.Apply(PointFromArray(c)).Array()
+	}
+	return mapped
+}
+
+// Inverse returns the homography mapping the other way, e.g. from the floorplan
+// back to camera pixels.
+func (h Homography) Inverse() (Homography, error) {
+	det := h.determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Homography{}, fmt.Errorf("homography is not invertible")
+	}
+	inverse := Homography{
+		{h[1][1]*h[2][2] - h[1][2]*h[2][1], h[0][2]*h[2][1] - h[0][1]*h[2][2], h[0][1]*h[1][2] - h[0][2]*h[1][1]},
+		{h[1][2]*h[2][0] - h[1][0]*h[2][2], h[0][0]*h[2][2] - h[0][2]*h[2][0], h[0][2]*h[1][0] - h[0][0]*h[1][2]},
+		{h[1][0]*h[2][1] - h[1][1]*h[2][0], h[0][1]*h[2][0] - h[0][0]*h[2][1], h[0][0]*h[1][1] - h[0][1]*h[1][0]},
+	}
+	inverse = inverse.scale(1 / det)
+	if inverse[2][2] != 0 {
+		inverse = inverse.scale(1 / inverse[2][2])
+	}
+	return inverse, nil
+}
+
+// ReprojectionError returns the root mean square and the maximum distance between
+// the mapped src points and dst, in destination units.
+func (h Homography) ReprojectionError(src, dst []Point) (float64, float64) {
+	n := min(len(src), len(dst))
+	if n == 0 {
+		return 0, 0
+	}
+	sumSquares, maxError := 0.0, 0.0
+	for i := 0; i < n; i++ {
+		d := h.Apply(src[i]).Distance(dst[i])
+		sumSquares += d * d
+		maxError = math.Max(maxError, d)
+	}
+	return math.Sqrt(sumSquares / float64(n)), maxError
+}
+
+func (h Homography) determinant() float64 {
+	return h[0][0]*(h[1][1]*h[2][2]-h[1][2]*h[2][1]) -
+		h[0][1]*(h[1][0]*h[2][2]-h[1][2]*h[2][0]) +
+		h[0][2]*(h[1][0]*h[2][1]-h[1][1]*h[2][0])
+}
+
+func (h Homography) multiply(o Homography) Homography {
+	var product Homography
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				product[i][j] += h[i][k] * o[k][j]
+			}
+		}
+	}
+	return product
+}
+
+func (h Homography) scale(s float64) Homography {
+	for i := range h {
+		for j := range h[i] {
+			h[i][j] *= s
+		}
+	}
+	return h
+}
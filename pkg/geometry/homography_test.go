@@ -0,0 +1,118 @@
+package geometry
+
+import (
+	"math"
+	mrand "math/rand"
+	"testing"
+)
+
+// cameraToFloor is a perspective view of a 10x20 meter floor: the far edge of the
+// floor appears narrow at the top of a 1920x1080 frame.
+var (
+	cameraCorners = []Point{{700, 300}, {1220, 300}, {1820, 1000}, {100, 1000}}
+	floorCorners  = []Point{{0, 20}, {10, 20}, {10, 0}, {0, 0}}
+)
+
+func TestNewHomography_FourPoints(t *testing.T) {
+	h, err := NewHomography(cameraCorners, floorCorners)
+	if err != nil {
+		t.Fatalf("NewHomography returned error: %v", err)
+	}
+	rms, maxError := h.ReprojectionError(cameraCorners, floorCorners)
+	if rms > 1e-9 || maxError > 1e-9 {
+		t.Fatalf("ReprojectionError: got rms %v, max %v", rms, maxError)
+	}
+	// The bottom center of the frame is the near middle of the floor, and a point
+	// halfway up the image of the floor is less than halfway across the floor,
+	// since far away floor is compressed near the top.
+	if got := h.Apply(Point{960, 1000}); !pointsClose(got, Point{5, 0}) {
+		t.Fatalf("Apply bottom center: got %v", got)
+	}
+	if got := h.Apply(Point{960, 650}); got.Y <= 0 || got.Y >= 10 {
+		t.Fatalf("Apply middle of the image: got %v, want before the floor's middle", got)
+	}
+	if h[2][2] != 1 {
+		t.Fatalf("homography should be scaled so h[2][2] is 1, got %v", h[2][2])
+	}
+}
+
+func TestNewHomography_LeastSquares(t *testing.T) {
+	truth, err := NewHomography(cameraCorners, floorCorners)
+	if err != nil {
+		t.Fatalf("NewHomography returned error: %v", err)
+	}
+	rng := mrand.New(mrand.NewSource(1))
+	var src, dst []Point
+	for i := 0; i < 50; i++ {
+		p := Point{X: 100 + rng.Float64()*1700, Y: 300 + rng.Float64()*700}
+		q := truth.Apply(p)
+		src = append(src, p)
+		dst = append(dst, Point{X: q.X + rng.NormFloat64()*0.01, Y: q.Y + rng.NormFloat64()*0.01})
+	}
+	h, err := NewHomography(src, dst)
+	if err != nil {
+		t.Fatalf("NewHomography returned error: %v", err)
+	}
+	if rms, _ := h.ReprojectionError(src, dst); rms > 0.02 {
+		t.Fatalf("ReprojectionError: got rms %v, want about the noise level", rms)
+	}
+	if got, want := h.Apply(Point{960, 650}), truth.Apply(Point{960, 650}); got.Distance(want) > 0.05 {
+		t.Fatalf("Apply: got %v, want %v", got, want)
+	}
+}
+
+func TestNewHomography_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, dst []Point
+	}{
+		{"too few points", cameraCorners[:3], floorCorners[:3]},
+		{"mismatched lengths", cameraCorners, floorCorners[:3]},
+		{"collinear points", []Point{{0, 0}, {1, 1}, {2, 2}, {3, 3}}, floorCorners},
+		{"identical points", []Point{{1, 1}, {1, 1}, {1, 1}, {1, 1}}, floorCorners},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHomography(tt.src, tt.dst); err == nil {
+				t.Fatalf("expected error")
+			}
+		})
+	}
+}
+
+func TestHomography_Inverse(t *testing.T) {
+	h, err := NewHomography(cameraCorners, floorCorners)
+	if err != nil {
+		t.Fatalf("NewHomography returned error: %v", err)
+	}
+	inverse, err := h.Inverse()
+	if err != nil {
+		t.Fatalf("Inverse returned error: %v", err)
+	}
+	for i, corner := range floorCorners {
+		if got := inverse.Apply(corner); got.Distance(cameraCorners[i]) > 1e-6 {
+			t.Fatalf("Inverse.Apply(%v): got %v, want %v", corner, got, cameraCorners[i])
+		}
+	}
+	if _, err := (Homography{}).Inverse(); err == nil {
+		t.Fatalf("expected error inverting a zero homography")
+	}
+}
+
+func TestHomography_ApplyCentroids(t *testing.T) {
+	// Centroids from BuildCentroids are in the 0-100 space, so map the normalized
+	// camera corners.
+	normalized := make([]Point, len(cameraCorners))
+	for i, c := range cameraCorners {
+		normalized[i] = FrameToNormalized(1920, 1080).Apply(c)
+	}
+	h, err := NewHomography(normalized, floorCorners)
+	if err != nil {
+		t.Fatalf("NewHomography returned error: %v", err)
+	}
+	traject := []interface{}{[]interface{}{940.0, 980.0, 980.0, 1020.0}}
+	got := h.ApplyCentroids(BuildCentroids(traject, 1920, 1080))
+	if len(got) != 1 || math.Abs(got[0][0]-5) > 1e-9 || math.Abs(got[0][1]) > 1e-9 {
+		t.Fatalf("ApplyCentroids: got %v, want [[5 0]]", got)
+	}
+}
@@ -0,0 +1,227 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// CentroidBounds returns the axis-aligned bounding box of centroids, or an empty box
+// at the origin when there are none. NaN coordinates never extend the box, unless
+// the first centroid has them.
+func CentroidBounds(centroids [][2]float64) BBox {
+	if len(centroids) == 0 {
+		return BBox{}
+	}
+	b := BBox{X1: centroids[0][0], Y1: centroids[0][1], X2: centroids[0][0], Y2: centroids[0][1]}
+	for _, c := range centroids[1:] {
+		if c[0] < b.X1 {
+			b.X1 = c[0]
+		}
+		if c[0] > b.X2 {
+			b.X2 = c[0]
+		}
+		if c[1] < b.Y1 {
+			b.Y1 = c[1]
+		}
+		if c[1] > b.Y2 {
+			b.Y2 = c[1]
+		}
+	}
+	return b
+}
+
+// cross returns the z component of (a - o) x (b - o): positive when o, a, b turn
+// counter-clockwise in a y-up system.
+func cross(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+// ConvexHull returns the convex hull of points with Andrew's monotone chain
+// algorithm, with a positive SignedArea and without collinear vertices. Fewer than
+// three distinct points give a hull of one or two vertices.
+func ConvexHull(points [][2]float64) Polygon {
+	sorted := NewPolygon(points)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	unique := sorted[:0]
+	for i, p := range sorted {
+		if i == 0 || p != sorted[i-1] {
+			unique = append(unique, p)
+		}
+	}
+	if len(unique) < 3 {
+		return unique
+	}
+
+	hull := make(Polygon, 0, 2*len(unique))
+	for _, p := range unique {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- {
+		p := unique[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	// The last point is the first one again.
+	return hull[:len(hull)-1]
+}
+
+// RotatedRect is a rectangle of Width by Height centered on Center and rotated by
+// Angle degrees, clockwise on screen.
+type RotatedRect struct {
+	Center Point   `json:"center" bson:"center"`
+	Width  float64 `json:"width" bson:"width"`
+	Height float64 `json:"height" bson:"height"`
+	Angle  float64 `json:"angle" bson:"angle"`
+}
+
+// Area returns the area of the rectangle.
+func (r RotatedRect) Area() float64 {
+	return r.Width * r.Height
+}
+
+// Corners returns the four corners of the rectangle as a polygon.
+func (r RotatedRect) Corners() Polygon {
+	w, h := r.Width/2, r.Height/2
+	return Rotate(r.Angle).Then(Translate(r.Center.X, r.Center.Y)).ApplyPolygon(Polygon{{-w, -h}, {w, -h}, {w, h}, {-w, h}})
+}
+
+// MinAreaRect returns the rotated rectangle of smallest area enclosing points,
+// using rotating calipers over the edges of their convex hull. Its Width is along
+// the rotated x axis and Angle is in [0, 90).
+func MinAreaRect(points [][2]float64) RotatedRect {
+	hull := ConvexHull(points)
+	switch len(hull) {
+	case 0:
+		return RotatedRect{}
+	case 1:
+		return RotatedRect{Center: hull[0]}
+	}
+
+	var best RotatedRect
+	bestArea := math.Inf(1)
+	for i := range hull {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		angle := math.Atan2(b.Y-a.Y, b.X-a.X)
+		// Rotate the hull so this edge is horizontal and take its bounds.
+		sin, cos := math.Sincos(-angle)
+		minU, maxU, minV, maxV := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+		for _, p := range hull {
+			u := p.X*cos - p.Y*sin
+			v := p.X*sin + p.Y*cos
+			minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+			minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+		}
+		width, height := maxU-minU, maxV-minV
+		if width*height >= bestArea {
+			continue
+		}
+		bestArea = width * height
+		// Rotate the center of the bounds back.
+		cu, cv := (minU+maxU)/2, (minV+maxV)/2
+		sin, cos = math.Sincos(angle)
+		degrees := angle * 180 / math.Pi
+		best = RotatedRect{Center: Point{X: cu*cos - cv*sin, Y: cu*sin + cv*cos}, Width: width, Height: height, Angle: degrees}
+	}
+	// Report the same rectangle with an angle in [0, 90).
+	for best.Angle < 0 {
+		best.Angle += 90
+		best.Width, best.Height = best.Height, best.Width
+	}
+	for best.Angle >= 90 {
+		best.Angle -= 90
+		best.Width, best.Height = best.Height, best.Width
+	}
+	return best
+}
+
+// ConcaveHull returns an outline of points that follows their shape more closely
+// than the convex hull, by digging into the hull edges (the gift-opening algorithm
+// of Park and Oh). An edge is replaced by two edges through the closest inner point
+// when its length divided by the distance to that point exceeds concavity, so lower
+// values give tighter outlines; around 2 suits most paths and +Inf returns the
+// convex hull. The result is a simple polygon containing every point.
+func ConcaveHull(points [][2]float64, concavity float64) Polygon {
+	hull := ConvexHull(points)
+	if len(hull) < 3 {
+		return hull
+	}
+	onHull := make(map[Point]bool, len(hull))
+	for _, p := range hull {
+		onHull[p] = true
+	}
+	var inner []Point
+	for _, c := range points {
+		p := PointFromArray(c)
+		if !onHull[p] {
+			onHull[p] = true
+			inner = append(inner, p)
+		}
+	}
+
+	for i := 0; i < len(hull) && len(inner) > 0; {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		prev, next := hull[(i+len(hull)-1)%len(hull)], hull[(i+2)%len(hull)]
+		candidate, best := -1, math.Inf(1)
+		for k, p := range inner {
+			d := segmentDistance(p.Array(), a.Array(), b.Array())
+			if d >= best {
+				continue
+			}
+			// Only dig towards points that are closest to this edge.
+			if d > segmentDistance(p.Array(), prev.Array(), a.Array()) || d > segmentDistance(p.Array(), b.Array(), next.Array()) {
+				continue
+			}
+			candidate, best = k, d
+		}
+		if candidate >= 0 {
+			p := inner[candidate]
+			nearest := math.Min(p.Distance(a), p.Distance(b))
+			if nearest > 0 && a.Distance(b)/nearest > concavity && !crossesHull(hull, i, p) {
+				hull = append(hull[:i+1], append(Polygon{p}, hull[i+1:]...)...)
+				inner = append(inner[:candidate], inner[candidate+1:]...)
+				// Examine the first new edge next.
+				continue
+			}
+		}
+		i++
+	}
+	return hull
+}
+
+// crossesHull reports whether the edges from hull[i] to p and from p to
+// hull[i+1] would cross any other hull edge.
+func crossesHull(hull Polygon, i int, p Point) bool {
+	a, b := hull[i], hull[(i+1)%len(hull)]
+	for j := range hull {
+		if j == i {
+			continue
+		}
+		c, d := hull[j], hull[(j+1)%len(hull)]
+		if (c != a && d != a && segmentsIntersect(a, p, c, d)) || (c != b && d != b && segmentsIntersect(p, b, c, d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentsIntersect reports whether the segments pq and rs share a point.
+func segmentsIntersect(p, q, r, s Point) bool {
+	d1, d2 := cross(r, s, p), cross(r, s, q)
+	d3, d4 := cross(p, q, r), cross(p, q, s)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(p, r, s)) || (d2 == 0 && onSegment(q, r, s)) ||
+		(d3 == 0 && onSegment(r, p, q)) || (d4 == 0 && onSegment(s, p, q))
+}
@@ -0,0 +1,130 @@
+package geometry
+
+import (
+	"math"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestCentroidBounds(t *testing.T) {
+	got := CentroidBounds([][2]float64{{3, 7}, {-1, 2}, {5, 4}})
+	if got != (BBox{X1: -1, Y1: 2, X2: 5, Y2: 7}) {
+		t.Fatalf("CentroidBounds: got %v", got)
+	}
+	if got := CentroidBounds(nil); got != (BBox{}) {
+		t.Fatalf("CentroidBounds(nil): got %v", got)
+	}
+}
+
+func TestConvexHull(t *testing.T) {
+	points := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {5, 5}, {5, 0}, {2, 8}, {10, 10}}
+	hull := ConvexHull(points)
+	want := Polygon{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	if !reflect.DeepEqual(hull, want) {
+		t.Fatalf("ConvexHull: got %v, want %v", hull, want)
+	}
+	if hull.SignedArea() <= 0 {
+		t.Fatalf("ConvexHull should have a positive SignedArea")
+	}
+
+	if got := ConvexHull([][2]float64{{1, 1}, {1, 1}}); !reflect.DeepEqual(got, Polygon{{1, 1}}) {
+		t.Fatalf("ConvexHull of one point: got %v", got)
+	}
+	if got := ConvexHull([][2]float64{{0, 0}, {1, 1}, {2, 2}}); !reflect.DeepEqual(got, Polygon{{0, 0}, {2, 2}}) {
+		t.Fatalf("ConvexHull of collinear points: got %v", got)
+	}
+	if got := ConvexHull(nil); len(got) != 0 {
+		t.Fatalf("ConvexHull(nil): got %v", got)
+	}
+}
+
+func TestConvexHull_ContainsAllPoints(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	points := make([][2]float64, 500)
+	for i := range points {
+		points[i] = [2]float64{rng.NormFloat64()*10 + 50, rng.NormFloat64()*10 + 50}
+	}
+	hull := ConvexHull(points)
+	for _, p := range points {
+		if !hull.ContainsCentroid(p) {
+			t.Fatalf("hull does not contain %v", p)
+		}
+	}
+	for i := range hull {
+		if cross(hull[i], hull[(i+1)%len(hull)], hull[(i+2)%len(hull)]) <= 0 {
+			t.Fatalf("hull is not strictly convex at %d", i)
+		}
+	}
+}
+
+func TestMinAreaRect(t *testing.T) {
+	// A 20x10 rectangle rotated by 30 degrees around (50, 50), with points inside.
+	rect := RotatedRect{Center: Point{50, 50}, Width: 20, Height: 10, Angle: 30}
+	var points [][2]float64
+	for _, corner := range rect.Corners() {
+		points = append(points, corner.Array())
+	}
+	points = append(points, [2]float64{50, 50}, [2]float64{52, 49})
+
+	got := MinAreaRect(points)
+	if math.Abs(got.Area()-200) > 1e-9 || math.Abs(got.Angle-30) > 1e-9 || !pointsClose(got.Center, rect.Center) {
+		t.Fatalf("MinAreaRect: got %+v, want %+v", got, rect)
+	}
+	if math.Abs(got.Width-20) > 1e-9 || math.Abs(got.Height-10) > 1e-9 {
+		t.Fatalf("MinAreaRect size: got %vx%v", got.Width, got.Height)
+	}
+
+	// Axis-aligned points give an angle of 0.
+	axis := MinAreaRect([][2]float64{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {1, 1}})
+	if axis.Angle != 0 || math.Abs(axis.Area()-8) > 1e-9 {
+		t.Fatalf("MinAreaRect axis-aligned: got %+v", axis)
+	}
+	if got := MinAreaRect([][2]float64{{3, 3}}); got != (RotatedRect{Center: Point{3, 3}}) {
+		t.Fatalf("MinAreaRect of one point: got %+v", got)
+	}
+	if got := MinAreaRect([][2]float64{{0, 0}, {3, 4}}); got.Area() > 1e-9 || math.Abs(math.Max(got.Width, got.Height)-5) > 1e-9 {
+		t.Fatalf("MinAreaRect of a segment: got %+v", got)
+	}
+}
+
+func TestConcaveHull(t *testing.T) {
+	// An L-shaped path: the convex hull covers the empty corner, the concave hull
+	// follows the L.
+	var points [][2]float64
+	for i := 0; i <= 20; i++ {
+		for _, w := range []float64{0, 5} {
+			points = append(points, [2]float64{w, float64(i) * 5})
+			points = append(points, [2]float64{float64(i) * 5, 95 + w})
+		}
+	}
+	convex := ConvexHull(points)
+	concave := ConcaveHull(points, 2)
+
+	if convex.Area() < 4000 {
+		t.Fatalf("convex hull area: got %v", convex.Area())
+	}
+	if concave.Area() > convex.Area()/2 {
+		t.Fatalf("concave hull area %v is not much smaller than convex %v", concave.Area(), convex.Area())
+	}
+	for _, p := range points {
+		if !concave.ContainsCentroid(p) {
+			t.Fatalf("concave hull does not contain %v", p)
+		}
+	}
+	// The hull must be simple: no two non-adjacent edges intersect.
+	for i := range concave {
+		for j := i + 2; j < len(concave); j++ {
+			if i == 0 && j == len(concave)-1 {
+				continue
+			}
+			if segmentsIntersect(concave[i], concave[(i+1)%len(concave)], concave[j], concave[(j+1)%len(concave)]) {
+				t.Fatalf("concave hull edges %d and %d intersect", i, j)
+			}
+		}
+	}
+
+	if got := ConcaveHull(points, math.Inf(1)); !reflect.DeepEqual(got, convex) {
+		t.Fatalf("ConcaveHull with infinite concavity should be the convex hull")
+	}
+}
@@ -0,0 +1,111 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// MergeOptions configures MergeBoxes.
+type MergeOptions struct {
+	// Distance is the largest gap between two boxes that still merges them. With 0
+	// only overlapping or touching boxes merge.
+	Distance float64
+	// MinArea drops merged regions smaller than it, e.g. to ignore noise.
+	MinArea float64
+}
+
+// BoxGap returns the distance between the closest points of a and b, or 0 when
+// they overlap or touch.
+func BoxGap(a, b BBox) float64 {
+	dx := math.Max(0, math.Max(a.X1-b.X2, b.X1-a.X2))
+	dy := math.Max(0, math.Max(a.Y1-b.Y2, b.Y1-a.Y2))
+	return math.Hypot(dx, dy)
+}
+
+// GroupBoxes returns the connected components of boxes, where two boxes are
+// connected when they are at most distance apart. Each group lists box indices in
+// increasing order, and groups are ordered by their first index.
+func GroupBoxes(boxes []BBox, distance float64) [][]int {
+	parent := make([]int, len(boxes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// Sweep over the boxes from left to right, only comparing boxes whose
+	// horizontal extents are close enough.
+	order := make([]int, len(boxes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return boxes[order[i]].X1 < boxes[order[j]].X1 })
+	for a, i := range order {
+		for _, j := range order[a+1:] {
+			if boxes[j].X1 > boxes[i].X2+distance {
+				break
+			}
+			if BoxGap(boxes[i], boxes[j]) <= distance {
+				ri, rj := find(i), find(j)
+				parent[max(ri, rj)] = min(ri, rj)
+			}
+		}
+	}
+
+	var groups [][]int
+	index := make(map[int]int)
+	for i := range boxes {
+		root := find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+// MergeBoxes merges overlapping or nearby boxes, such as the changed-pixel
+// rectangles of a motion detector, into the bounding boxes of the regions they
+// form. Merging repeats until no two regions are within opts.Distance, since the
+// union of a group can reach boxes that none of its members did. Regions smaller
+// than opts.MinArea are dropped.
+func MergeBoxes(boxes []BBox, opts MergeOptions) []BBox {
+	regions := append([]BBox(nil), boxes...)
+	for {
+		groups := GroupBoxes(regions, opts.Distance)
+		if len(groups) == len(regions) {
+			break
+		}
+		merged := make([]BBox, len(groups))
+		for g, members := range groups {
+			region := regions[members[0]]
+			for _, i := range members[1:] {
+				region = region.Union(regions[i])
+			}
+			merged[g] = region
+		}
+		regions = merged
+	}
+
+	kept := regions[:0]
+	for _, region := range regions {
+		if region.Area() >= opts.MinArea {
+			kept = append(kept, region)
+		}
+	}
+	return kept
+}
+
+// MergeMotionRegions merges boxes like MergeBoxes and returns the regions in the
+// traject format consumed by BuildCentroids.
+func MergeMotionRegions(boxes []BBox, opts MergeOptions) []interface{} {
+	return BoxesToTraject(MergeBoxes(boxes, opts))
+}
@@ -0,0 +1,127 @@
+package geometry
+
+import (
+	mrand "math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestBoxGap(t *testing.T) {
+	a := NewBBoxXYWH(0, 0, 10, 10)
+	tests := []struct {
+		name string
+		b    BBox
+		want float64
+	}{
+		{"overlapping", NewBBoxXYWH(5, 5, 10, 10), 0},
+		{"touching", NewBBoxXYWH(10, 0, 10, 10), 0},
+		{"horizontal gap", NewBBoxXYWH(13, 2, 5, 5), 3},
+		{"vertical gap", NewBBoxXYWH(2, 14, 5, 5), 4},
+		{"diagonal gap", NewBBoxXYWH(13, 14, 5, 5), 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BoxGap(a, tt.b); got != tt.want {
+				t.Fatalf("BoxGap: got %v, want %v", got, tt.want)
+			}
+			if got := BoxGap(tt.b, a); got != tt.want {
+				t.Fatalf("BoxGap reversed: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupBoxes(t *testing.T) {
+	boxes := []BBox{
+		NewBBoxXYWH(0, 0, 10, 10),   // 0: chain with 2 through 4
+		NewBBoxXYWH(100, 0, 10, 10), // 1: alone
+		NewBBoxXYWH(22, 0, 10, 10),  // 2: chained through 4
+		NewBBoxXYWH(50, 50, 5, 5),   // 3: alone
+		NewBBoxXYWH(11, 0, 10, 10),  // 4: 1 from 0 and 2
+	}
+	if got := GroupBoxes(boxes, 0); !reflect.DeepEqual(got, [][]int{{0}, {1}, {2}, {3}, {4}}) {
+		t.Fatalf("GroupBoxes(0): got %v", got)
+	}
+	if got := GroupBoxes(boxes, 1); !reflect.DeepEqual(got, [][]int{{0, 2, 4}, {1}, {3}}) {
+		t.Fatalf("GroupBoxes(1): got %v", got)
+	}
+	if got := GroupBoxes(nil, 1); got != nil {
+		t.Fatalf("GroupBoxes(nil): got %v", got)
+	}
+}
+
+func TestGroupBoxes_MatchesBruteForce(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	boxes := make([]BBox, 300)
+	for i := range boxes {
+		boxes[i] = NewBBoxXYWH(rng.Float64()*1000, rng.Float64()*1000, rng.Float64()*20, rng.Float64()*20)
+	}
+	groups := GroupBoxes(boxes, 5)
+
+	label := make([]int, len(boxes))
+	for g, members := range groups {
+		for _, i := range members {
+			label[i] = g
+		}
+	}
+	for i := range boxes {
+		for j := range boxes {
+			if BoxGap(boxes[i], boxes[j]) <= 5 && label[i] != label[j] {
+				t.Fatalf("boxes %d and %d are close but in different groups", i, j)
+			}
+		}
+	}
+}
+
+func TestMergeBoxes(t *testing.T) {
+	boxes := []BBox{
+		// A person made of overlapping changed-pixel blobs.
+		NewBBoxXYWH(100, 100, 20, 20),
+		NewBBoxXYWH(110, 115, 20, 40),
+		NewBBoxXYWH(105, 150, 15, 30),
+		// Leaves moving nearby, 3 pixels away.
+		NewBBoxXYWH(133, 100, 10, 10),
+		// Noise.
+		NewBBoxXYWH(400, 300, 2, 2),
+	}
+
+	got := MergeBoxes(boxes, MergeOptions{})
+	want := []BBox{{X1: 100, Y1: 100, X2: 130, Y2: 180}, NewBBoxXYWH(133, 100, 10, 10), NewBBoxXYWH(400, 300, 2, 2)}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergeBoxes: got %v, want %v", got, want)
+	}
+
+	got = MergeBoxes(boxes, MergeOptions{Distance: 5, MinArea: 10})
+	want = []BBox{{X1: 100, Y1: 100, X2: 143, Y2: 180}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergeBoxes with distance and min area: got %v, want %v", got, want)
+	}
+	if boxes[0] != NewBBoxXYWH(100, 100, 20, 20) {
+		t.Fatalf("MergeBoxes modified its input")
+	}
+}
+
+func TestMergeBoxes_RepeatsUntilStable(t *testing.T) {
+	// Boxes 0 and 1 merge into a region that then overlaps box 2, which neither
+	// of them touched.
+	boxes := []BBox{
+		NewBBoxXYWH(0, 0, 10, 2),
+		NewBBoxXYWH(8, 0, 2, 20),
+		NewBBoxXYWH(0, 15, 3, 3),
+	}
+	if got := MergeBoxes(boxes, MergeOptions{}); !reflect.DeepEqual(got, []BBox{{X1: 0, Y1: 0, X2: 10, Y2: 20}}) {
+		t.Fatalf("MergeBoxes: got %v", got)
+	}
+}
+
+func TestMergeMotionRegions(t *testing.T) {
+	boxes := []BBox{NewBBoxXYWH(0, 0, 20, 20), NewBBoxXYWH(10, 10, 20, 20), NewBBoxXYWH(100, 50, 10, 10)}
+	traject := MergeMotionRegions(boxes, MergeOptions{})
+	if len(traject) != 2 {
+		t.Fatalf("MergeMotionRegions: got %d regions, want 2", len(traject))
+	}
+	centroids := BuildCentroids(traject, 200, 100)
+	if !reflect.DeepEqual(centroids, [][2]float64{{7.5, 15}, {52.5, 55}}) {
+		t.Fatalf("BuildCentroids of regions: got %v", centroids)
+	}
+}
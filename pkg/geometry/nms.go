@@ -0,0 +1,151 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// Detection is a scored bounding box produced by an object detector.
+type Detection struct {
+	Box   BBox    `json:"box" bson:"box"`
+	Score float64 `json:"score" bson:"score"`
+	Class string  `json:"class,omitempty" bson:"class,omitempty"`
+}
+
+// NMSMethod selects how overlapping detections are suppressed.
+type NMSMethod int
+
+const (
+	// NMSHard drops every detection overlapping a higher scored one by more than
+	// the IoU threshold.
+	NMSHard NMSMethod = iota
+	// NMSLinear multiplies the score of overlapping detections by 1 - IoU (soft-NMS).
+	NMSLinear
+	// NMSGaussian multiplies the score of every detection by exp(-IoU²/sigma) (soft-NMS).
+	NMSGaussian
+)
+
+// NMSOptions configures NMS.
+type NMSOptions struct {
+	Method NMSMethod
+	// IoUThreshold is the overlap above which NMSHard and NMSLinear act. Defaults to 0.5.
+	IoUThreshold float64
+	// ScoreThreshold drops detections scoring below it, both before suppression and
+	// after soft-NMS decayed their score.
+	ScoreThreshold float64
+	// Sigma is the Gaussian soft-NMS parameter. Defaults to 0.5.
+	Sigma float64
+	// PerClass only lets detections of the same class suppress each other.
+	PerClass bool
+	// MaxDetections limits the number of returned detections when greater than 0.
+	MaxDetections int
+}
+
+// NMS performs non-maximum suppression and returns the kept detections sorted by
+// descending score. With a soft method the returned scores are the decayed ones.
+// The input slice is not modified.
+func NMS(detections []Detection, opts NMSOptions) []Detection {
+	if opts.IoUThreshold <= 0 {
+		opts.IoUThreshold = 0.5
+	}
+	if opts.Sigma <= 0 {
+		opts.Sigma = 0.5
+	}
+
+	var kept []Detection
+	if opts.PerClass {
+		groups := make(map[string][]Detection)
+		var classes []string
+		for _, d := range detections {
+			if _, ok := groups[d.Class]; !ok {
+				classes = append(classes, d.Class)
+			}
+			groups[d.Class] = append(groups[d.Class], d)
+		}
+		for _, class := range classes {
+			kept = append(kept, suppress(groups[class], opts)...)
+		}
+		sortByScore(kept)
+	} else {
+		kept = suppress(detections, opts)
+	}
+
+	if opts.MaxDetections > 0 && len(kept) > opts.MaxDetections {
+		kept = kept[:opts.MaxDetections]
+	}
+	return kept
+}
+
+func sortByScore(detections []Detection) {
+	sort.SliceStable(detections, func(i, j int) bool { return detections[i].Score > detections[j].Score })
+}
+
+// suppress runs NMS on a single group of detections.
+func suppress(detections []Detection, opts NMSOptions) []Detection {
+	candidates := make([]Detection, 0, len(detections))
+	for _, d := range detections {
+		if d.Score >= opts.ScoreThreshold {
+			candidates = append(candidates, d)
+		}
+	}
+	sortByScore(candidates)
+
+	if opts.Method == NMSHard {
+		kept := make([]Detection, 0, len(candidates))
+		suppressed := make([]bool, len(candidates))
+		for i, d := range candidates {
+			if suppressed[i] {
+				continue
+			}
+			kept = append(kept, d)
+			area := d.Box.Area()
+			for j := i + 1; j < len(candidates); j++ {
+				if suppressed[j] {
+					continue
+				}
+				intersection := IntersectionArea(d.Box, candidates[j].Box)
+				if intersection == 0 {
+					continue
+				}
+				if intersection/(area+candidates[j].Box.Area()-intersection) > opts.IoUThreshold {
+					suppressed[j] = true
+				}
+			}
+		}
+		return kept
+	}
+
+	// Soft-NMS: repeatedly keep the best remaining detection and decay the others.
+	kept := make([]Detection, 0, len(candidates))
+	remaining := candidates
+	for len(remaining) > 0 {
+		best := 0
+		for i := range remaining {
+			if remaining[i].Score > remaining[best].Score {
+				best = i
+			}
+		}
+		top := remaining[best]
+		kept = append(kept, top)
+		remaining[best] = remaining[len(remaining)-1]
+		remaining = remaining[:len(remaining)-1]
+
+		next := remaining[:0]
+		for _, d := range remaining {
+			iou := IoU(top.Box, d.Box)
+			switch opts.Method {
+			case NMSLinear:
+				if iou > opts.IoUThreshold {
+					d.Score *= 1 - iou
+				}
+			case NMSGaussian:
+				d.Score *= math.Exp(-(iou * iou) / opts.Sigma)
+			}
+			if d.Score >= opts.ScoreThreshold && d.Score > 0 {
+				next = append(next, d)
+			}
+		}
+		remaining = next
+	}
+	return kept
+}
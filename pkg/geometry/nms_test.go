@@ -0,0 +1,111 @@
+package geometry
+
+import (
+	mrand "math/rand"
+	"testing"
+)
+
+func TestNMS_Hard(t *testing.T) {
+	detections := []Detection{
+		{Box: BBox{0, 0, 10, 10}, Score: 0.8, Class: "person"},
+		{Box: BBox{1, 1, 11, 11}, Score: 0.9, Class: "person"},
+		{Box: BBox{50, 50, 60, 60}, Score: 0.7, Class: "person"},
+		{Box: BBox{0, 0, 10, 10}, Score: 0.6, Class: "car"},
+		{Box: BBox{80, 80, 90, 90}, Score: 0.1, Class: "person"},
+	}
+
+	got := NMS(detections, NMSOptions{IoUThreshold: 0.5, ScoreThreshold: 0.2})
+	if len(got) != 2 || got[0].Score != 0.9 || got[1].Score != 0.7 {
+		t.Fatalf("class agnostic: got %+v, want scores [0.9 0.7]", got)
+	}
+
+	got = NMS(detections, NMSOptions{IoUThreshold: 0.5, ScoreThreshold: 0.2, PerClass: true})
+	if len(got) != 3 || got[0].Score != 0.9 || got[1].Score != 0.7 || got[2].Class != "car" {
+		t.Fatalf("per class: got %+v, want scores [0.9 0.7 0.6(car)]", got)
+	}
+
+	got = NMS(detections, NMSOptions{PerClass: true, MaxDetections: 1})
+	if len(got) != 1 || got[0].Score != 0.9 {
+		t.Fatalf("max detections: got %+v", got)
+	}
+
+	if detections[0].Score != 0.8 {
+		t.Fatalf("NMS modified its input: %+v", detections[0])
+	}
+	if got := NMS(nil, NMSOptions{}); len(got) != 0 {
+		t.Fatalf("empty input: got %+v", got)
+	}
+}
+
+func TestNMS_Soft(t *testing.T) {
+	detections := []Detection{
+		{Box: BBox{0, 0, 10, 10}, Score: 0.9},
+		{Box: BBox{0, 0, 10, 8}, Score: 0.8},    // IoU 0.8 with the first
+		{Box: BBox{5, 0, 15, 10}, Score: 0.7},   // IoU 1/3 with the first
+		{Box: BBox{50, 50, 60, 60}, Score: 0.5}, // disjoint
+	}
+
+	linear := NMS(detections, NMSOptions{Method: NMSLinear, IoUThreshold: 0.5, ScoreThreshold: 0.1})
+	if len(linear) != 4 {
+		t.Fatalf("linear: got %d detections, want 4: %+v", len(linear), linear)
+	}
+	// The 0.8 detection decays to 0.8 * (1 - 0.8) = 0.16 and falls to last place.
+	if linear[0].Score != 0.9 || linear[1].Score != 0.7 || linear[2].Score != 0.5 {
+		t.Fatalf("linear: unexpected order %+v", linear)
+	}
+	if got := linear[3].Score; got < 0.159 || got > 0.161 {
+		t.Fatalf("linear: decayed score %v, want 0.16", got)
+	}
+
+	gaussian := NMS(detections, NMSOptions{Method: NMSGaussian, Sigma: 0.5, ScoreThreshold: 0.3})
+	for _, d := range gaussian {
+		if d.Box == detections[1].Box {
+			t.Fatalf("gaussian: heavily overlapping detection should fall below the threshold, got %+v", d)
+		}
+	}
+	if len(gaussian) != 3 || gaussian[0].Score != 0.9 {
+		t.Fatalf("gaussian: got %+v", gaussian)
+	}
+	if gaussian[1].Score >= 0.7 {
+		t.Fatalf("gaussian: partially overlapping detection should decay, got %v", gaussian[1].Score)
+	}
+}
+
+func randomDetections(n int, seed int64) []Detection {
+	rng := mrand.New(mrand.NewSource(seed))
+	detections := make([]Detection, n)
+	classes := []string{"person", "car", "bicycle"}
+	for i := range detections {
+		x, y := rng.Float64()*1820, rng.Float64()*980
+		detections[i] = Detection{
+			Box:   NewBBoxXYWH(x, y, 20+rng.Float64()*80, 40+rng.Float64()*60),
+			Score: rng.Float64(),
+			Class: classes[rng.Intn(len(classes))],
+		}
+	}
+	return detections
+}
+
+func BenchmarkNMS_Hard1k(b *testing.B) {
+	detections := randomDetections(1000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NMS(detections, NMSOptions{IoUThreshold: 0.5})
+	}
+}
+
+func BenchmarkNMS_HardPerClass1k(b *testing.B) {
+	detections := randomDetections(1000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NMS(detections, NMSOptions{IoUThreshold: 0.5, PerClass: true})
+	}
+}
+
+func BenchmarkNMS_Gaussian1k(b *testing.B) {
+	detections := randomDetections(1000, 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NMS(detections, NMSOptions{Method: NMSGaussian, ScoreThreshold: 0.05})
+	}
+}
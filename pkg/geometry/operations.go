@@ -7,25 +7,10 @@ func CompressCentroids(centroids [][2]float64, maxPoints int) [][2]float64 {
 		return centroids
 	}
 
-	minX, maxX := centroids[0][0], centroids[0][0]
-	minY, maxY := centroids[0][1], centroids[0][1]
-	for _, c := range centroids[1:] {
-		if c[0] < minX {
-			minX = c[0]
-		}
-		if c[0] > maxX {
-			maxX = c[0]
-		}
-		if c[1] < minY {
-			minY = c[1]
-		}
-		if c[1] > maxY {
-			maxY = c[1]
-		}
-	}
-
-	width := maxX - minX
-	height := maxY - minY
+	bounds := CentroidBounds(centroids)
+	minX, minY := bounds.X1, bounds.Y1
+	width := bounds.Width()
+	height := bounds.Height()
 	if width == 0 && height == 0 {
 		return centroids[:1]
 	}
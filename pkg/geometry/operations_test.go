@@ -413,3 +413,14 @@ func TestBuildCentroids_OneDimensionProvided(t *testing.T) {
 	want := [][2]float64{{5, 5}}
 	assertPointsAlmostEqual(t, got, want, 1e-9)
 }
+
+func TestCompressCentroids_NaNDoesNotSpreadIntoBounds(t *testing.T) {
+	in := [][2]float64{{0, 0}, {math.NaN(), 5}, {10, 10}, {20, 20}, {30, 30}, {40, 40}}
+	got := CompressCentroids(in, 3)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 points, got %v", got)
+	}
+	if got[0] != [2]float64{0, 0} || !math.IsNaN(got[1][0]) || got[1][1] != 5 || got[2] != [2]float64{30, 30} {
+		t.Fatalf("expected [[0 0] [NaN 5] [30 30]], got %v", got)
+	}
+}
@@ -0,0 +1,63 @@
+package geometry
+
+import "math"
+
+// Intersect returns the overlapping part of both boxes, or an empty box when they do
+// not overlap.
+func (b BBox) Intersect(other BBox) BBox {
+	intersection := BBox{
+		X1: math.Max(b.X1, other.X1),
+		Y1: math.Max(b.Y1, other.Y1),
+		X2: math.Min(b.X2, other.X2),
+		Y2: math.Min(b.Y2, other.Y2),
+	}
+	if intersection.IsEmpty() {
+		return BBox{}
+	}
+	return intersection
+}
+
+// Union returns the smallest box enclosing both boxes.
+func (b BBox) Union(other BBox) BBox {
+	return BBox{
+		X1: math.Min(b.X1, other.X1),
+		Y1: math.Min(b.Y1, other.Y1),
+		X2: math.Max(b.X2, other.X2),
+		Y2: math.Max(b.Y2, other.Y2),
+	}
+}
+
+// IntersectionArea returns the area shared by both boxes.
+func IntersectionArea(a, b BBox) float64 {
+	w := math.Min(a.X2, b.X2) - math.Max(a.X1, b.X1)
+	h := math.Min(a.Y2, b.Y2) - math.Max(a.Y1, b.Y1)
+	if w <= 0 || h <= 0 {
+		return 0
+	}
+	return w * h
+}
+
+// IoU returns the intersection over union of both boxes, between 0 and 1.
+func IoU(a, b BBox) float64 {
+	intersection := IntersectionArea(a, b)
+	if intersection == 0 {
+		return 0
+	}
+	return intersection / (a.Area() + b.Area() - intersection)
+}
+
+// GIoU returns the generalized intersection over union, between -1 and 1. Unlike IoU
+// it keeps decreasing as disjoint boxes move further apart.
+func GIoU(a, b BBox) float64 {
+	intersection := IntersectionArea(a, b)
+	union := a.Area() + b.Area() - intersection
+	enclosing := a.Union(b).Area()
+	if enclosing == 0 {
+		return 0
+	}
+	iou := 0.0
+	if union > 0 {
+		iou = intersection / union
+	}
+	return iou - (enclosing-union)/enclosing
+}
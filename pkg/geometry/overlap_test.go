@@ -0,0 +1,54 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntersectAndUnion(t *testing.T) {
+	a := BBox{0, 0, 10, 10}
+	b := BBox{5, 5, 15, 20}
+	if got := a.Intersect(b); got != (BBox{5, 5, 10, 10}) {
+		t.Fatalf("Intersect: got %+v, want {5 5 10 10}", got)
+	}
+	if got := a.Union(b); got != (BBox{0, 0, 15, 20}) {
+		t.Fatalf("Union: got %+v, want {0 0 15 20}", got)
+	}
+	if got := a.Intersect(BBox{20, 20, 30, 30}); got != (BBox{}) {
+		t.Fatalf("Intersect disjoint: got %+v, want empty", got)
+	}
+}
+
+func TestIoU(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b BBox
+		iou  float64
+		giou float64
+	}{
+		{"identical", BBox{0, 0, 10, 10}, BBox{0, 0, 10, 10}, 1, 1},
+		{"half overlap", BBox{0, 0, 10, 10}, BBox{5, 0, 15, 10}, 50.0 / 150.0, 50.0 / 150.0},
+		{"contained", BBox{0, 0, 10, 10}, BBox{0, 0, 5, 10}, 0.5, 0.5},
+		{"touching", BBox{0, 0, 10, 10}, BBox{10, 0, 20, 10}, 0, 0},
+		{"disjoint", BBox{0, 0, 10, 10}, BBox{20, 0, 30, 10}, 0, -1.0 / 3.0},
+		{"empty boxes", BBox{}, BBox{}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IoU(tt.a, tt.b); math.Abs(got-tt.iou) > 1e-12 {
+				t.Fatalf("IoU(%v, %v): got %v, want %v", tt.a, tt.b, got, tt.iou)
+			}
+			if got := GIoU(tt.a, tt.b); math.Abs(got-tt.giou) > 1e-12 {
+				t.Fatalf("GIoU(%v, %v): got %v, want %v", tt.a, tt.b, got, tt.giou)
+			}
+			if IoU(tt.a, tt.b) != IoU(tt.b, tt.a) {
+				t.Fatalf("IoU is not symmetric for %v, %v", tt.a, tt.b)
+			}
+		})
+	}
+
+	if got := IntersectionArea(BBox{0, 0, 10, 10}, BBox{5, 5, 15, 15}); got != 25 {
+		t.Fatalf("IntersectionArea: got %v, want 25", got)
+	}
+}
@@ -0,0 +1,226 @@
+package geometry
+
+import "math"
+
+// Polygon is a closed polygon given by its vertices in order; the last vertex
+// connects back to the first. It is encoded as [[x, y], ...] in JSON and BSON.
+// Zones drawn on a camera view are usually stored in the same normalized 0-100
+// space as the centroids of BuildCentroids.
+type Polygon []Point
+
+// boundaryEpsilon is the distance within which a point counts as lying on an edge.
+const boundaryEpsilon = 1e-9
+
+// NewPolygon builds a polygon from [x, y] pairs.
+func NewPolygon(points [][2]float64) Polygon {
+	polygon := make(Polygon, len(points))
+	for i, p := range points {
+		polygon[i] = PointFromArray(p)
+	}
+	return polygon
+}
+
+// SignedArea returns the shoelace area of the polygon: positive when the vertices
+// run counter-clockwise in a y-up system (clockwise on screen, where y points down).
+func (p Polygon) SignedArea() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+	sum := 0.0
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum / 2
+}
+
+// Area returns the area enclosed by the polygon.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.SignedArea())
+}
+
+// Centroid returns the center of mass of the polygon. For degenerate polygons
+// without area it returns the mean of the vertices.
+func (p Polygon) Centroid() Point {
+	if len(p) == 0 {
+		return Point{}
+	}
+	area := p.SignedArea()
+	if area == 0 {
+		var sum Point
+		for _, v := range p {
+			sum = sum.Add(v)
+		}
+		return Point{X: sum.X / float64(len(p)), Y: sum.Y / float64(len(p))}
+	}
+	var cx, cy float64
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		cross := a.X*b.Y - b.X*a.Y
+		cx += (a.X + b.X) * cross
+		cy += (a.Y + b.Y) * cross
+	}
+	return Point{X: cx / (6 * area), Y: cy / (6 * area)}
+}
+
+// Bounds returns the axis-aligned bounding box of the polygon.
+func (p Polygon) Bounds() BBox {
+	if len(p) == 0 {
+		return BBox{}
+	}
+	bounds := BBox{X1: p[0].X, Y1: p[0].Y, X2: p[0].X, Y2: p[0].Y}
+	for _, v := range p[1:] {
+		bounds.X1 = math.Min(bounds.X1, v.X)
+		bounds.Y1 = math.Min(bounds.Y1, v.Y)
+		bounds.X2 = math.Max(bounds.X2, v.X)
+		bounds.Y2 = math.Max(bounds.Y2, v.Y)
+	}
+	return bounds
+}
+
+// Contains reports whether pt lies inside the polygon or on its boundary. It uses
+// the even-odd rule, which handles concave polygons and polygons whose vertices
+// touch another edge.
+func (p Polygon) Contains(pt Point) bool {
+	if len(p) < 3 {
+		return false
+	}
+	inside := false
+	for i := range p {
+		a, b := p[i], p[(i+1)%len(p)]
+		if onSegment(pt, a, b) {
+			return true
+		}
+		// Half-open rule: an edge counts when it straddles the horizontal ray, so a
+		// vertex shared by two edges is never counted twice.
+		if (a.Y > pt.Y) != (b.Y > pt.Y) {
+			x := a.X + (pt.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+			if pt.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// ContainsCentroid reports whether a centroid as returned by BuildCentroids lies in
+// the polygon. Both must use the same coordinate space.
+func (p Polygon) ContainsCentroid(c [2]float64) bool {
+	return p.Contains(PointFromArray(c))
+}
+
+// onSegment reports whether pt lies on the segment ab.
+func onSegment(pt, a, b Point) bool {
+	cross := (b.X-a.X)*(pt.Y-a.Y) - (b.Y-a.Y)*(pt.X-a.X)
+	length := math.Hypot(b.X-a.X, b.Y-a.Y)
+	if length == 0 {
+		return pt.Distance(a) <= boundaryEpsilon
+	}
+	if math.Abs(cross)/length > boundaryEpsilon {
+		return false
+	}
+	return pt.X >= math.Min(a.X, b.X)-boundaryEpsilon && pt.X <= math.Max(a.X, b.X)+boundaryEpsilon &&
+		pt.Y >= math.Min(a.Y, b.Y)-boundaryEpsilon && pt.Y <= math.Max(a.Y, b.Y)+boundaryEpsilon
+}
+
+// ClipToBox returns the part of the polygon inside the box (Sutherland-Hodgman).
+// Concave polygons may yield zero-width connecting edges, which do not affect the
+// area.
+func (p Polygon) ClipToBox(b BBox) Polygon {
+	type edge struct {
+		inside    func(Point) bool
+		intersect func(Point, Point) Point
+	}
+	lerpX := func(x float64) func(Point, Point) Point {
+		return func(a, c Point) Point {
+			t := (x - a.X) / (c.X - a.X)
+			return Point{X: x, Y: a.Y + t*(c.Y-a.Y)}
+		}
+	}
+	lerpY := func(y float64) func(Point, Point) Point {
+		return func(a, c Point) Point {
+			t := (y - a.Y) / (c.Y - a.Y)
+			return Point{X: a.X + t*(c.X-a.X), Y: y}
+		}
+	}
+	edges := []edge{
+		{func(q Point) bool { return q.X >= b.X1 }, lerpX(b.X1)},
+		{func(q Point) bool { return q.X <= b.X2 }, lerpX(b.X2)},
+		{func(q Point) bool { return q.Y >= b.Y1 }, lerpY(b.Y1)},
+		{func(q Point) bool { return q.Y <= b.Y2 }, lerpY(b.Y2)},
+	}
+
+	output := append(Polygon(nil), p...)
+	for _, e := range edges {
+		if len(output) == 0 {
+			break
+		}
+		input := output
+		output = make(Polygon, 0, len(input)+2)
+		previous := input[len(input)-1]
+		for _, current := range input {
+			switch {
+			case e.inside(current) && e.inside(previous):
+				output = append(output, current)
+			case e.inside(current):
+				output = append(output, e.intersect(previous, current), current)
+			case e.inside(previous):
+				output = append(output, e.intersect(previous, current))
+			}
+			previous = current
+		}
+	}
+	return output
+}
+
+// IntersectionArea returns the area of the box that lies inside the polygon.
+func (p Polygon) IntersectionArea(b BBox) float64 {
+	if b.IsEmpty() || len(p) < 3 {
+		return 0
+	}
+	return p.ClipToBox(b).Area()
+}
+
+// BoxOverlap returns the fraction of the box area, between 0 and 1, that lies
+// inside the polygon.
+func (p Polygon) BoxOverlap(b BBox) float64 {
+	area := b.Area()
+	if area == 0 {
+		return 0
+	}
+	return math.Min(p.IntersectionArea(b)/area, 1)
+}
+
+// Normalize converts frame pixel coordinates into the 0-100 space used by
+// BuildCentroids.
+func (p Polygon) Normalize(frameWidth, frameHeight float64) Polygon {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return p
+	}
+	return p.scale(100.0/frameWidth, 100.0/frameHeight)
+}
+
+// Denormalize converts 0-100 coordinates back into frame pixel coordinates.
+func (p Polygon) Denormalize(frameWidth, frameHeight float64) Polygon {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return p
+	}
+	return p.scale(frameWidth/100.0, frameHeight/100.0)
+}
+
+func (p Polygon) scale(sx, sy float64) Polygon {
+	scaled := make(Polygon, len(p))
+	for i, v := range p {
+		scaled[i] = Point{X: v.X * sx, Y: v.Y * sy}
+	}
+	return scaled
+}
+
+// Normalize converts a box in frame pixel coordinates into the 0-100 space used by
+// BuildCentroids.
+func (b BBox) Normalize(frameWidth, frameHeight float64) BBox {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return b
+	}
+	return b.Scale(100.0/frameWidth, 100.0/frameHeight)
+}
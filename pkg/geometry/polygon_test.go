@@ -0,0 +1,166 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// lShape is a concave polygon covering a 10x10 square minus its top-right quadrant.
+var lShape = NewPolygon([][2]float64{{0, 0}, {5, 0}, {5, 5}, {10, 5}, {10, 10}, {0, 10}})
+
+func TestPolygonAreaAndCentroid(t *testing.T) {
+	square := NewPolygon([][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}})
+	if square.Area() != 100 {
+		t.Fatalf("square area: got %v, want 100", square.Area())
+	}
+	if c := square.Centroid(); c != (Point{5, 5}) {
+		t.Fatalf("square centroid: got %+v, want {5 5}", c)
+	}
+
+	if lShape.Area() != 75 {
+		t.Fatalf("L-shape area: got %v, want 75", lShape.Area())
+	}
+	// Centroid of the 5x10 left bar (2.5, 5) and the 5x5 bottom-right square (7.5, 7.5).
+	want := Point{X: (2.5*50 + 7.5*25) / 75, Y: (5*50 + 7.5*25) / 75}
+	if c := lShape.Centroid(); math.Abs(c.X-want.X) > 1e-9 || math.Abs(c.Y-want.Y) > 1e-9 {
+		t.Fatalf("L-shape centroid: got %+v, want %+v", c, want)
+	}
+
+	reversed := Polygon{square[3], square[2], square[1], square[0]}
+	if reversed.SignedArea() != -square.SignedArea() || reversed.Area() != 100 {
+		t.Fatalf("reversed winding: signed %v, area %v", reversed.SignedArea(), reversed.Area())
+	}
+
+	line := NewPolygon([][2]float64{{0, 0}, {2, 2}, {4, 4}})
+	if line.Area() != 0 || line.Centroid() != (Point{2, 2}) {
+		t.Fatalf("degenerate polygon: area %v centroid %+v", line.Area(), line.Centroid())
+	}
+	if lShape.Bounds() != (BBox{0, 0, 10, 10}) {
+		t.Fatalf("bounds: got %+v", lShape.Bounds())
+	}
+}
+
+func TestPolygonContains(t *testing.T) {
+	// Two squares touching in a single vertex at (5, 5).
+	bowtie := NewPolygon([][2]float64{{0, 0}, {5, 0}, {5, 5}, {10, 5}, {10, 10}, {5, 10}, {5, 5}, {0, 5}})
+
+	tests := []struct {
+		name    string
+		polygon Polygon
+		point   Point
+		want    bool
+	}{
+		{"concave inside", lShape, Point{2, 8}, true},
+		{"concave notch", lShape, Point{8, 2}, false},
+		{"on edge", lShape, Point{5, 2}, true},
+		{"on vertex", lShape, Point{10, 10}, true},
+		{"ray through vertex", lShape, Point{2, 5}, true},
+		{"outside", lShape, Point{11, 5}, false},
+		{"self-touching first lobe", bowtie, Point{2, 2}, true},
+		{"self-touching second lobe", bowtie, Point{7, 7}, true},
+		{"self-touching empty quadrant", bowtie, Point{7, 2}, false},
+		{"self-touching shared vertex", bowtie, Point{5, 5}, true},
+		{"too few vertices", Polygon{{0, 0}, {1, 1}}, Point{0, 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.polygon.Contains(tt.point); got != tt.want {
+				t.Fatalf("Contains(%+v): got %v, want %v", tt.point, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolygonContainsCentroid(t *testing.T) {
+	// A zone covering the left half of the view in normalized coordinates.
+	zone := NewPolygon([][2]float64{{0, 0}, {50, 0}, {50, 100}, {0, 100}})
+	traject := []interface{}{
+		[]interface{}{100.0, 100.0, 300.0, 300.0},
+		[]interface{}{1000.0, 100.0, 1200.0, 300.0},
+	}
+	centroids := BuildCentroids(traject, 1920, 1080)
+	if !zone.ContainsCentroid(centroids[0]) || zone.ContainsCentroid(centroids[1]) {
+		t.Fatalf("ContainsCentroid: got %v, %v for %v", zone.ContainsCentroid(centroids[0]), zone.ContainsCentroid(centroids[1]), centroids)
+	}
+}
+
+func TestPolygonBoxIntersection(t *testing.T) {
+	tests := []struct {
+		name    string
+		box     BBox
+		area    float64
+		overlap float64
+	}{
+		{"fully inside", BBox{1, 6, 4, 9}, 9, 1},
+		{"in the notch", BBox{6, 1, 9, 4}, 0, 0},
+		{"straddling the notch", BBox{4, 4, 6, 6}, 3, 0.75},
+		{"partly outside", BBox{-5, -5, 5, 5}, 25, 0.25},
+		{"covering everything", BBox{-10, -10, 20, 20}, 75, 75.0 / 900.0},
+		{"empty box", BBox{1, 1, 1, 1}, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lShape.IntersectionArea(tt.box); math.Abs(got-tt.area) > 1e-9 {
+				t.Fatalf("IntersectionArea(%+v): got %v, want %v", tt.box, got, tt.area)
+			}
+			if got := lShape.BoxOverlap(tt.box); math.Abs(got-tt.overlap) > 1e-9 {
+				t.Fatalf("BoxOverlap(%+v): got %v, want %v", tt.box, got, tt.overlap)
+			}
+		})
+	}
+}
+
+func TestPolygonNormalize(t *testing.T) {
+	pixels := NewPolygon([][2]float64{{0, 0}, {960, 0}, {960, 540}})
+	normalized := pixels.Normalize(1920, 1080)
+	if !reflect.DeepEqual(normalized, NewPolygon([][2]float64{{0, 0}, {50, 0}, {50, 50}})) {
+		t.Fatalf("Normalize: got %v", normalized)
+	}
+	if !reflect.DeepEqual(normalized.Denormalize(1920, 1080), pixels) {
+		t.Fatalf("Denormalize: got %v", normalized.Denormalize(1920, 1080))
+	}
+	if !reflect.DeepEqual(pixels.Normalize(0, 0), pixels) {
+		t.Fatalf("Normalize without frame size should keep coordinates")
+	}
+
+	box := BBox{100, 100, 300, 300}.Normalize(1920, 1080)
+	c := box.Center()
+	want := BuildCentroids([]interface{}{[]interface{}{100.0, 100.0, 300.0, 300.0}}, 1920, 1080)[0]
+	if math.Abs(c.X-want[0]) > 1e-9 || math.Abs(c.Y-want[1]) > 1e-9 {
+		t.Fatalf("normalized box center %+v does not match BuildCentroids %v", c, want)
+	}
+}
+
+func TestPolygonEncoding(t *testing.T) {
+	type zone struct {
+		Polygon Polygon `json:"polygon" bson:"polygon"`
+	}
+	in := zone{Polygon: NewPolygon([][2]float64{{0, 0}, {10, 0}, {10, 10}})}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if string(data) != `{"polygon":[[0,0],[10,0],[10,10]]}` {
+		t.Fatalf("json.Marshal: got %s", data)
+	}
+	var fromJSON zone
+	if err := json.Unmarshal(data, &fromJSON); err != nil || !reflect.DeepEqual(fromJSON, in) {
+		t.Fatalf("json round trip: got %+v, err %v", fromJSON, err)
+	}
+
+	raw, err := bson.Marshal(in)
+	if err != nil {
+		t.Fatalf("bson.Marshal: %v", err)
+	}
+	var fromBSON zone
+	if err := bson.Unmarshal(raw, &fromBSON); err != nil || !reflect.DeepEqual(fromBSON, in) {
+		t.Fatalf("bson round trip: got %+v, err %v", fromBSON, err)
+	}
+}
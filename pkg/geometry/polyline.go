@@ -0,0 +1,170 @@
+package geometry
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// DefaultPolylinePrecision is the number of decimals kept by the polyline and
+// delta-varint encodings when no precision is given, as in Google's format.
+const DefaultPolylinePrecision = 5
+
+// maxPolylinePrecision keeps scaled coordinates well within int64.
+const maxPolylinePrecision = 10
+
+func polylineFactor(precision int) (float64, error) {
+	if precision <= 0 {
+		precision = DefaultPolylinePrecision
+	}
+	if precision > maxPolylinePrecision {
+		return 0, fmt.Errorf("polyline precision %d is above the maximum of %d", precision, maxPolylinePrecision)
+	}
+	return math.Pow10(precision), nil
+}
+
+// EncodePolyline encodes points in Google's encoded polyline format, keeping
+// precision decimals (DefaultPolylinePrecision when 0). The values of each pair
+// are encoded in order; Google Maps expects [lat, lng] pairs, and the normalized
+// centroids of BuildCentroids usually need no more than 2 decimals.
+func EncodePolyline(points [][2]float64, precision int) (string, error) {
+	factor, err := polylineFactor(precision)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	var previous [2]int64
+	for _, p := range points {
+		for k := range p {
+			value := int64(math.Round(p[k] * factor))
+			encodePolylineValue(&b, value-previous[k])
+			previous[k] = value
+		}
+	}
+	return b.String(), nil
+}
+
+func encodePolylineValue(b *strings.Builder, delta int64) {
+	v := uint64(delta << 1)
+	if delta < 0 {
+		v = ^v
+	}
+	for v >= 0x20 {
+		b.WriteByte(byte((0x20 | (v & 0x1f)) + 63))
+		v >>= 5
+	}
+	b.WriteByte(byte(v + 63))
+}
+
+// DecodePolyline decodes a polyline encoded with the same precision by
+// EncodePolyline.
+func DecodePolyline(encoded string, precision int) ([][2]float64, error) {
+	factor, err := polylineFactor(precision)
+	if err != nil {
+		return nil, err
+	}
+	var points [][2]float64
+	var current [2]int64
+	for i := 0; i < len(encoded); {
+		var p [2]float64
+		for k := range p {
+			var v uint64
+			var shift uint
+			for {
+				if i >= len(encoded) {
+					return nil, fmt.Errorf("polyline ends in the middle of a value")
+				}
+				c := encoded[i]
+				i++
+				if c < 63 || c > 126 {
+					return nil, fmt.Errorf("invalid polyline character %q at position %d", c, i-1)
+				}
+				if shift > 60 {
+					return nil, fmt.Errorf("polyline value at position %d is too large", i-1)
+				}
+				chunk := uint64(c - 63)
+				v |= (chunk & 0x1f) << shift
+				shift += 5
+				if chunk < 0x20 {
+					break
+				}
+			}
+			delta := int64(v >> 1)
+			if v&1 == 1 {
+				delta = ^delta
+			}
+			current[k] += delta
+			p[k] = float64(current[k]) / factor
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// EncodeDeltaVarint encodes points in a compact binary format, keeping precision
+// decimals (DefaultPolylinePrecision when 0): the precision and the number of
+// points as unsigned varints, followed by the difference of each value with the
+// previous point as signed varints. It is smaller than an encoded polyline and
+// suits MQTT payloads and BSON binary fields.
+func EncodeDeltaVarint(points [][2]float64, precision int) ([]byte, error) {
+	if precision <= 0 {
+		precision = DefaultPolylinePrecision
+	}
+	factor, err := polylineFactor(precision)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, 2+4*len(points))
+	data = binary.AppendUvarint(data, uint64(precision))
+	data = binary.AppendUvarint(data, uint64(len(points)))
+	var previous [2]int64
+	for _, p := range points {
+		for k := range p {
+			value := int64(math.Round(p[k] * factor))
+			data = binary.AppendVarint(data, value-previous[k])
+			previous[k] = value
+		}
+	}
+	return data, nil
+}
+
+// DecodeDeltaVarint decodes points encoded by EncodeDeltaVarint.
+func DecodeDeltaVarint(data []byte) ([][2]float64, error) {
+	precision, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid delta-varint header")
+	}
+	data = data[n:]
+	if precision == 0 || precision > maxPolylinePrecision {
+		return nil, fmt.Errorf("invalid delta-varint precision %d", precision)
+	}
+	factor := math.Pow10(int(precision))
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid delta-varint header")
+	}
+	data = data[n:]
+	// Every value takes at least one byte.
+	if count > uint64(len(data))/2 {
+		return nil, fmt.Errorf("delta-varint data holds fewer than %d points", count)
+	}
+
+	points := make([][2]float64, count)
+	var current [2]int64
+	for i := range points {
+		for k := range points[i] {
+			delta, n := binary.Varint(data)
+			if n <= 0 {
+				return nil, fmt.Errorf("invalid delta-varint value for point %d", i)
+			}
+			data = data[n:]
+			current[k] += delta
+			points[i][k] = float64(current[k]) / factor
+		}
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%d unexpected bytes after delta-varint points", len(data))
+	}
+	return points, nil
+}
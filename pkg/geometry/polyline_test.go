@@ -0,0 +1,141 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+)
+
+// googleExample is the example of Google's polyline documentation, as [lat, lng].
+var googleExample = [][2]float64{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+func randomWalk(rng *mrand.Rand, n int) [][2]float64 {
+	points := make([][2]float64, n)
+	x, y := 50.0, 50.0
+	for i := range points {
+		x = math.Max(0, math.Min(100, x+rng.NormFloat64()))
+		y = math.Max(0, math.Min(100, y+rng.NormFloat64()))
+		points[i] = [2]float64{math.Round(x*100) / 100, math.Round(y*100) / 100}
+	}
+	return points
+}
+
+func pathsClose(a, b [][2]float64, tolerance float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i][0]-b[i][0]) > tolerance || math.Abs(a[i][1]-b[i][1]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEncodePolyline_GoogleExample(t *testing.T) {
+	encoded, err := EncodePolyline(googleExample, 0)
+	if err != nil {
+		t.Fatalf("EncodePolyline returned error: %v", err)
+	}
+	if encoded != "_p~iF~ps|U_ulLnnqC_mqNvxq`@" {
+		t.Fatalf("EncodePolyline: got %q", encoded)
+	}
+	decoded, err := DecodePolyline(encoded, DefaultPolylinePrecision)
+	if err != nil {
+		t.Fatalf("DecodePolyline returned error: %v", err)
+	}
+	if !pathsClose(decoded, googleExample, 1e-9) {
+		t.Fatalf("DecodePolyline: got %v", decoded)
+	}
+}
+
+func TestPolyline_RoundTrip(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	points := randomWalk(rng, 500)
+	for _, precision := range []int{1, 2, 5, 7} {
+		tolerance := 0.5/math.Pow10(precision) + 1e-9
+
+		encoded, err := EncodePolyline(points, precision)
+		if err != nil {
+			t.Fatalf("EncodePolyline(%d) returned error: %v", precision, err)
+		}
+		decoded, err := DecodePolyline(encoded, precision)
+		if err != nil || !pathsClose(decoded, points, tolerance) {
+			t.Fatalf("polyline round trip with precision %d failed: %v", precision, err)
+		}
+
+		data, err := EncodeDeltaVarint(points, precision)
+		if err != nil {
+			t.Fatalf("EncodeDeltaVarint(%d) returned error: %v", precision, err)
+		}
+		decoded, err = DecodeDeltaVarint(data)
+		if err != nil || !pathsClose(decoded, points, tolerance) {
+			t.Fatalf("delta-varint round trip with precision %d failed: %v", precision, err)
+		}
+	}
+
+	// Coordinates at the kept precision survive exactly.
+	data, _ := EncodeDeltaVarint(points, 2)
+	if decoded, _ := DecodeDeltaVarint(data); !reflect.DeepEqual(decoded, points) {
+		t.Fatalf("delta-varint with precision 2 is not exact")
+	}
+}
+
+func TestPolyline_Empty(t *testing.T) {
+	if encoded, _ := EncodePolyline(nil, 0); encoded != "" {
+		t.Fatalf("EncodePolyline(nil): got %q", encoded)
+	}
+	if decoded, err := DecodePolyline("", 0); err != nil || decoded != nil {
+		t.Fatalf("DecodePolyline(\"\"): got %v, %v", decoded, err)
+	}
+	data, _ := EncodeDeltaVarint(nil, 0)
+	if decoded, err := DecodeDeltaVarint(data); err != nil || len(decoded) != 0 {
+		t.Fatalf("delta-varint of no points: got %v, %v", decoded, err)
+	}
+}
+
+func TestPolyline_SizeComparedToJSON(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(2))
+	points := randomWalk(rng, 200)
+
+	jsonData, _ := json.Marshal(points)
+	polyline, _ := EncodePolyline(points, 2)
+	binaryData, _ := EncodeDeltaVarint(points, 2)
+	t.Logf("200 points: JSON %d bytes, polyline %d bytes, delta-varint %d bytes", len(jsonData), len(polyline), len(binaryData))
+
+	if len(polyline)*3 > len(jsonData) {
+		t.Fatalf("polyline (%d bytes) should be under a third of JSON (%d bytes)", len(polyline), len(jsonData))
+	}
+	if len(binaryData) >= len(polyline) {
+		t.Fatalf("delta-varint (%d bytes) should be smaller than polyline (%d bytes)", len(binaryData), len(polyline))
+	}
+}
+
+func TestPolyline_Errors(t *testing.T) {
+	if _, err := EncodePolyline(googleExample, 11); err == nil {
+		t.Fatalf("expected error for precision above the maximum")
+	}
+	if _, err := EncodeDeltaVarint(googleExample, 11); err == nil {
+		t.Fatalf("expected error for precision above the maximum")
+	}
+	for _, encoded := range []string{"_p~iF~ps|U_", "_p~iF", "_p~iF ps|U", "~~~~~~~~~~~~~~~"} {
+		if _, err := DecodePolyline(encoded, 0); err == nil {
+			t.Fatalf("DecodePolyline(%q): expected error", encoded)
+		}
+	}
+
+	data, _ := EncodeDeltaVarint(googleExample, 5)
+	for name, corrupt := range map[string][]byte{
+		"empty":             nil,
+		"truncated":         data[:len(data)-1],
+		"trailing bytes":    append(append([]byte{}, data...), 0),
+		"invalid precision": {0, 1, 0, 0},
+		"too many points":   {5, 100, 0, 0},
+	} {
+		if _, err := DecodeDeltaVarint(corrupt); err == nil {
+			t.Fatalf("DecodeDeltaVarint(%s): expected error", name)
+		}
+	}
+}
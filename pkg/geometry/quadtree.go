@@ -0,0 +1,253 @@
+package geometry
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+const (
+	// quadtreeNodeItems is the number of items a node holds before it splits.
+	quadtreeNodeItems = 16
+	// quadtreeMaxDepth bounds the depth of the tree, e.g. for many identical points.
+	quadtreeMaxDepth = 20
+)
+
+// IndexEntry is an item stored in a Quadtree. Points are stored as boxes without
+// area.
+type IndexEntry struct {
+	ID  int  `json:"id" bson:"id"`
+	Box BBox `json:"box" bson:"box"`
+}
+
+// Quadtree is an in-memory spatial index of points and boxes, each identified by an
+// ID chosen by the caller, e.g. the position of a detection in a slice. Every item
+// is stored in the smallest node that fully contains it, so boxes crossing node
+// borders stay higher up in the tree. Items outside the bounds of the tree are kept
+// at its root and still found, only more slowly.
+type Quadtree struct {
+	root  *quadNode
+	boxes map[int]BBox
+}
+
+type quadNode struct {
+	bounds   BBox
+	depth    int
+	items    []IndexEntry
+	children *[4]*quadNode
+}
+
+// NewQuadtree returns an empty index covering bounds, e.g. Frame(100, 100) for the
+// normalized centroids of BuildCentroids.
+func NewQuadtree(bounds BBox) *Quadtree {
+	return &Quadtree{root: &quadNode{bounds: bounds}, boxes: make(map[int]BBox)}
+}
+
+// Len returns the number of items in the index.
+func (q *Quadtree) Len() int {
+	return len(q.boxes)
+}
+
+// Entries returns every item in the index ordered by ID.
+func (q *Quadtree) Entries() []IndexEntry {
+	entries := make([]IndexEntry, 0, len(q.boxes))
+	for id, b := range q.boxes {
+		entries = append(entries, IndexEntry{ID: id, Box: b})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries
+}
+
+// InsertPoint adds a point, replacing any item with the same ID.
+func (q *Quadtree) InsertPoint(id int, p Point) {
+	q.InsertBox(id, BBox{X1: p.X, Y1: p.Y, X2: p.X, Y2: p.Y})
+}
+
+// InsertBox adds a box, replacing any item with the same ID.
+func (q *Quadtree) InsertBox(id int, b BBox) {
+	q.Delete(id)
+	q.boxes[id] = b
+	q.root.insert(IndexEntry{ID: id, Box: b})
+}
+
+// Delete removes the item with the given ID and reports whether it was present.
+func (q *Quadtree) Delete(id int) bool {
+	b, ok := q.boxes[id]
+	if !ok {
+		return false
+	}
+	delete(q.boxes, id)
+	q.root.remove(id, b)
+	return true
+}
+
+// Search returns the IDs of the items intersecting region, borders included.
+func (q *Quadtree) Search(region BBox) []int {
+	var found []int
+	q.root.search(region, func(e IndexEntry) {
+		found = append(found, e.ID)
+	})
+	return found
+}
+
+// SearchPolygon returns the IDs of the points inside zone and of the boxes that
+// overlap it, e.g. all detections within a zone.
+func (q *Quadtree) SearchPolygon(zone Polygon) []int {
+	var found []int
+	q.root.search(zone.Bounds(), func(e IndexEntry) {
+		if e.Box.Width() == 0 || e.Box.Height() == 0 {
+			if zone.Contains(e.Box.Center()) {
+				found = append(found, e.ID)
+			}
+			return
+		}
+		if zone.IntersectionArea(e.Box) > 0 {
+			found = append(found, e.ID)
+		}
+	})
+	return found
+}
+
+// Nearest returns the IDs of the k items closest to p, nearest first. The distance
+// to a box is 0 when p lies inside it.
+func (q *Quadtree) Nearest(p Point, k int) []int {
+	if k <= 0 {
+		return nil
+	}
+	queue := &nearestQueue{{node: q.root}}
+	var found []int
+	for queue.Len() > 0 && len(found) < k {
+		candidate := heap.Pop(queue).(nearestCandidate)
+		if candidate.node == nil {
+			found = append(found, candidate.id)
+			continue
+		}
+		for _, e := range candidate.node.items {
+			heap.Push(queue, nearestCandidate{distance: boxDistance(p, e.Box), id: e.ID})
+		}
+		if candidate.node.children != nil {
+			for _, child := range candidate.node.children {
+				heap.Push(queue, nearestCandidate{distance: boxDistance(p, child.bounds), node: child})
+			}
+		}
+	}
+	return found
+}
+
+// boxDistance returns the distance from p to the closest point of b.
+func boxDistance(p Point, b BBox) float64 {
+	dx := math.Max(math.Max(b.X1-p.X, 0), p.X-b.X2)
+	dy := math.Max(math.Max(b.Y1-p.Y, 0), p.Y-b.Y2)
+	return math.Hypot(dx, dy)
+}
+
+// containsBox reports whether b lies inside outer, borders included.
+func containsBox(outer, b BBox) bool {
+	return b.X1 >= outer.X1 && b.X2 <= outer.X2 && b.Y1 >= outer.Y1 && b.Y2 <= outer.Y2
+}
+
+// intersectsBox reports whether a and b share at least one point.
+func intersectsBox(a, b BBox) bool {
+	return a.X1 <= b.X2 && b.X1 <= a.X2 && a.Y1 <= b.Y2 && b.Y1 <= a.Y2
+}
+
+// child returns the child that fully contains b, or nil.
+func (n *quadNode) child(b BBox) *quadNode {
+	if n.children == nil {
+		return nil
+	}
+	for _, c := range n.children {
+		if containsBox(c.bounds, b) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (n *quadNode) insert(e IndexEntry) {
+	if c := n.child(e.Box); c != nil {
+		c.insert(e)
+		return
+	}
+	n.items = append(n.items, e)
+	if n.children == nil && len(n.items) > quadtreeNodeItems && n.depth < quadtreeMaxDepth {
+		n.split()
+	}
+}
+
+func (n *quadNode) split() {
+	b := n.bounds
+	mx, my := b.X1+b.Width()/2, b.Y1+b.Height()/2
+	n.children = &[4]*quadNode{
+		{bounds: BBox{X1: b.X1, Y1: b.Y1, X2: mx, Y2: my}, depth: n.depth + 1},
+		{bounds: BBox{X1: mx, Y1: b.Y1, X2: b.X2, Y2: my}, depth: n.depth + 1},
+		{bounds: BBox{X1: b.X1, Y1: my, X2: mx, Y2: b.Y2}, depth: n.depth + 1},
+		{bounds: BBox{X1: mx, Y1: my, X2: b.X2, Y2: b.Y2}, depth: n.depth + 1},
+	}
+	items := n.items
+	n.items = nil
+	for _, e := range items {
+		n.insert(e)
+	}
+}
+
+func (n *quadNode) remove(id int, b BBox) {
+	if c := n.child(b); c != nil {
+		c.remove(id, b)
+		return
+	}
+	for i, e := range n.items {
+		if e.ID == id {
+			last := len(n.items) - 1
+			n.items[i] = n.items[last]
+			n.items = n.items[:last]
+			return
+		}
+	}
+}
+
+func (n *quadNode) search(region BBox, visit func(IndexEntry)) {
+	for _, e := range n.items {
+		if intersectsBox(region, e.Box) {
+			visit(e)
+		}
+	}
+	if n.children == nil {
+		return
+	}
+	for _, c := range n.children {
+		if intersectsBox(region, c.bounds) {
+			c.search(region, visit)
+		}
+	}
+}
+
+// nearestCandidate is either a node to expand or, when node is nil, an item.
+type nearestCandidate struct {
+	distance float64
+	id       int
+	node     *quadNode
+}
+
+type nearestQueue []nearestCandidate
+
+func (q nearestQueue) Len() int { return len(q) }
+func (q nearestQueue) Less(i, j int) bool {
+	if q[i].distance != q[j].distance {
+		return q[i].distance < q[j].distance
+	}
+	// Expand nodes before reporting items at the same distance, and report items
+	// at the same distance by ID, so the result does not depend on the tree layout.
+	if (q[i].node == nil) != (q[j].node == nil) {
+		return q[i].node != nil
+	}
+	return q[i].id < q[j].id
+}
+func (q nearestQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *nearestQueue) Push(x any)   { *q = append(*q, x.(nearestCandidate)) }
+func (q *nearestQueue) Pop() any {
+	old := *q
+	candidate := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return candidate
+}
@@ -0,0 +1,216 @@
+package geometry
+
+import (
+	mrand "math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func randomEntries(rng *mrand.Rand, n int) []IndexEntry {
+	entries := make([]IndexEntry, n)
+	for i := range entries {
+		x, y := rng.Float64()*100, rng.Float64()*100
+		box := BBox{X1: x, Y1: y, X2: x, Y2: y}
+		if i%3 == 0 {
+			box = NewBBoxXYWH(x, y, rng.Float64()*10, rng.Float64()*10)
+		}
+		entries[i] = IndexEntry{ID: i, Box: box}
+	}
+	return entries
+}
+
+func buildQuadtree(entries []IndexEntry) *Quadtree {
+	tree := NewQuadtree(Frame(100, 100))
+	for _, e := range entries {
+		tree.InsertBox(e.ID, e.Box)
+	}
+	return tree
+}
+
+func bruteForceSearch(entries []IndexEntry, region BBox) []int {
+	var found []int
+	for _, e := range entries {
+		if intersectsBox(region, e.Box) {
+			found = append(found, e.ID)
+		}
+	}
+	return found
+}
+
+func bruteForceNearest(entries []IndexEntry, p Point, k int) []int {
+	// best holds the k closest entries so far, by distance and then by ID.
+	type candidate struct {
+		distance float64
+		id       int
+	}
+	var best []candidate
+	for _, e := range entries {
+		c := candidate{distance: boxDistance(p, e.Box), id: e.ID}
+		i := sort.Search(len(best), func(i int) bool {
+			return c.distance < best[i].distance || (c.distance == best[i].distance && c.id < best[i].id)
+		})
+		if i >= k {
+			continue
+		}
+		if len(best) < k {
+			best = append(best, candidate{})
+		}
+		copy(best[i+1:], best[i:])
+		best[i] = c
+	}
+	var ids []int
+	for _, c := range best {
+		ids = append(ids, c.id)
+	}
+	return ids
+}
+
+func sortedIDs(ids []int) []int {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+func TestQuadtree_MatchesBruteForce(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	entries := randomEntries(rng, 5000)
+	// A few items outside the bounds of the tree.
+	entries = append(entries, IndexEntry{ID: 5000, Box: NewBBoxXYWH(120, 50, 1, 1)}, IndexEntry{ID: 5001, Box: NewBBoxXYWH(-10, -10, 20, 20)})
+	tree := buildQuadtree(entries)
+	if tree.Len() != len(entries) {
+		t.Fatalf("Len: got %d, want %d", tree.Len(), len(entries))
+	}
+
+	for i := 0; i < 50; i++ {
+		region := NewBBoxXYWH(rng.Float64()*110-5, rng.Float64()*110-5, rng.Float64()*30, rng.Float64()*30)
+		if got, want := sortedIDs(tree.Search(region)), bruteForceSearch(entries, region); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Search(%v): got %d items, want %d", region, len(got), len(want))
+		}
+
+		p := Point{X: rng.Float64()*120 - 10, Y: rng.Float64()*120 - 10}
+		if got, want := tree.Nearest(p, 10), bruteForceNearest(entries, p, 10); !reflect.DeepEqual(got, want) {
+			t.Fatalf("Nearest(%v): got %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestQuadtree_Delete(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(2))
+	entries := randomEntries(rng, 1000)
+	tree := buildQuadtree(entries)
+
+	var kept []IndexEntry
+	for _, e := range entries {
+		if e.ID%2 == 0 {
+			if !tree.Delete(e.ID) {
+				t.Fatalf("Delete(%d) = false, want true", e.ID)
+			}
+			continue
+		}
+		kept = append(kept, e)
+	}
+	if tree.Delete(0) {
+		t.Fatalf("Delete of a missing ID = true, want false")
+	}
+	if tree.Len() != len(kept) || !reflect.DeepEqual(tree.Entries(), kept) {
+		t.Fatalf("Entries after delete: got %d, want %d", tree.Len(), len(kept))
+	}
+	region := Frame(100, 100)
+	if got, want := sortedIDs(tree.Search(region)), bruteForceSearch(kept, region); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Search after delete: got %d items, want %d", len(got), len(want))
+	}
+
+	// Inserting an existing ID moves the item.
+	tree.InsertPoint(1, Point{X: 150, Y: 150})
+	if got := tree.Search(NewBBoxXYWH(149, 149, 2, 2)); !reflect.DeepEqual(got, []int{1}) {
+		t.Fatalf("Search after move: got %v", got)
+	}
+	if tree.Len() != len(kept) {
+		t.Fatalf("Len after move: got %d, want %d", tree.Len(), len(kept))
+	}
+}
+
+func TestQuadtree_SearchPolygon(t *testing.T) {
+	tree := NewQuadtree(Frame(100, 100))
+	tree.InsertPoint(1, Point{X: 10, Y: 10})
+	tree.InsertPoint(2, Point{X: 40, Y: 40})
+	tree.InsertPoint(3, Point{X: 5, Y: 30})
+	tree.InsertBox(4, NewBBoxXYWH(25, 5, 10, 10))
+	tree.InsertBox(5, NewBBoxXYWH(35, 5, 10, 10))
+
+	// The lower-left triangle below the diagonal from (0,0) to (50,50).
+	zone := NewPolygon([][2]float64{{0, 0}, {50, 50}, {50, 0}})
+	if got := sortedIDs(tree.SearchPolygon(zone)); !reflect.DeepEqual(got, []int{1, 2, 4, 5}) {
+		t.Fatalf("SearchPolygon: got %v, want [1 2 4 5]", got)
+	}
+}
+
+func TestQuadtree_IdenticalPoints(t *testing.T) {
+	tree := NewQuadtree(Frame(100, 100))
+	for i := 0; i < 200; i++ {
+		tree.InsertPoint(i, Point{X: 50, Y: 50})
+	}
+	if got := tree.Search(NewBBoxXYWH(50, 50, 0, 0)); len(got) != 200 {
+		t.Fatalf("Search: got %d items, want 200", len(got))
+	}
+	if got := tree.Nearest(Point{X: 0, Y: 0}, 3); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Fatalf("Nearest: got %v", got)
+	}
+	if got := tree.Nearest(Point{}, 0); got != nil {
+		t.Fatalf("Nearest(k=0): got %v", got)
+	}
+}
+
+// benchmarkEntries are shared by the benchmarks; building 1M items takes a while.
+var benchmarkEntries []IndexEntry
+var benchmarkTree *Quadtree
+
+func benchmarkSetup(b *testing.B) {
+	b.Helper()
+	if benchmarkTree == nil {
+		rng := mrand.New(mrand.NewSource(1))
+		benchmarkEntries = make([]IndexEntry, 1_000_000)
+		for i := range benchmarkEntries {
+			x, y := rng.Float64()*100, rng.Float64()*100
+			benchmarkEntries[i] = IndexEntry{ID: i, Box: BBox{X1: x, Y1: y, X2: x, Y2: y}}
+		}
+		benchmarkTree = buildQuadtree(benchmarkEntries)
+	}
+	b.ResetTimer()
+}
+
+func BenchmarkQuadtreeInsert1M(b *testing.B) {
+	benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		buildQuadtree(benchmarkEntries)
+	}
+}
+
+func BenchmarkQuadtreeSearch1M(b *testing.B) {
+	benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		benchmarkTree.Search(NewBBoxXYWH(40, 40, 2, 2))
+	}
+}
+
+func BenchmarkBruteForceSearch1M(b *testing.B) {
+	benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		bruteForceSearch(benchmarkEntries, NewBBoxXYWH(40, 40, 2, 2))
+	}
+}
+
+func BenchmarkQuadtreeNearest1M(b *testing.B) {
+	benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		benchmarkTree.Nearest(Point{X: 40, Y: 40}, 10)
+	}
+}
+
+func BenchmarkBruteForceNearest1M(b *testing.B) {
+	benchmarkSetup(b)
+	for i := 0; i < b.N; i++ {
+		bruteForceNearest(benchmarkEntries, Point{X: 40, Y: 40}, 10)
+	}
+}
@@ -0,0 +1,219 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// PathDistance measures how different two centroid paths are.
+type PathDistance func(a, b [][2]float64) float64
+
+func centroidDistance(p, q [2]float64) float64 {
+	return math.Hypot(p[0]-q[0], p[1]-q[1])
+}
+
+// FrechetDistance returns the discrete Fréchet distance between two paths: the
+// shortest leash that lets two walkers traverse them in order without going back.
+// Unlike Hausdorff it takes the direction of travel into account. It returns 0 for
+// two empty paths and +Inf when only one is empty.
+func FrechetDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return emptyPathDistance(a, b)
+	}
+	previous := make([]float64, len(b))
+	current := make([]float64, len(b))
+	for i := range a {
+		for j := range b {
+			d := centroidDistance(a[i], b[j])
+			switch {
+			case i == 0 && j == 0:
+				current[j] = d
+			case i == 0:
+				current[j] = math.Max(current[j-1], d)
+			case j == 0:
+				current[j] = math.Max(previous[j], d)
+			default:
+				current[j] = math.Max(math.Min(previous[j], math.Min(previous[j-1], current[j-1])), d)
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)-1]
+}
+
+// HausdorffDistance returns the largest distance from a point of one path to the
+// closest point of the other, ignoring the order of the points.
+func HausdorffDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return emptyPathDistance(a, b)
+	}
+	return math.Max(directedHausdorff(a, b), directedHausdorff(b, a))
+}
+
+func directedHausdorff(a, b [][2]float64) float64 {
+	distance := 0.0
+	for _, p := range a {
+		closest := math.Inf(1)
+		for _, q := range b {
+			closest = math.Min(closest, centroidDistance(p, q))
+		}
+		distance = math.Max(distance, closest)
+	}
+	return distance
+}
+
+// DTWDistance returns the dynamic time warping distance between two paths: the
+// smallest sum of point distances over an alignment that matches every point of
+// one path to one or more points of the other, in order. It grows with the number
+// of points, so compare paths sampled at similar rates.
+func DTWDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return emptyPathDistance(a, b)
+	}
+	previous := make([]float64, len(b))
+	current := make([]float64, len(b))
+	for i := range a {
+		for j := range b {
+			d := centroidDistance(a[i], b[j])
+			switch {
+			case i == 0 && j == 0:
+				current[j] = d
+			case i == 0:
+				current[j] = current[j-1] + d
+			case j == 0:
+				current[j] = previous[j] + d
+			default:
+				current[j] = math.Min(previous[j], math.Min(previous[j-1], current[j-1])) + d
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)-1]
+}
+
+func emptyPathDistance(a, b [][2]float64) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	return math.Inf(1)
+}
+
+// ClusterOptions configures ClusterPaths.
+type ClusterOptions struct {
+	// Epsilon is the largest distance between two paths in the same neighbourhood.
+	Epsilon float64
+	// MinPoints is the number of paths, including itself, a path needs within
+	// Epsilon to start a cluster. Defaults to 3.
+	MinPoints int
+	// Distance compares two paths. Defaults to FrechetDistance.
+	Distance PathDistance
+	// MaxPoints compresses the representative paths with CompressCentroids when
+	// greater than 0.
+	MaxPoints int
+}
+
+// PathCluster is a group of similar paths found by ClusterPaths.
+type PathCluster struct {
+	// Members are the indices of the paths in the cluster.
+	Members []int
+	// Medoid is the index of the member closest to all the others.
+	Medoid int
+	// Representative is the medoid path, compressed with CompressCentroids when
+	// ClusterOptions.MaxPoints is set.
+	Representative [][2]float64
+}
+
+// ClusterPaths groups paths, such as the centroids returned by BuildCentroids, with
+// DBSCAN over the pairwise path distances, to discover the typical routes through a
+// scene. It returns the clusters in order of discovery and the indices of the paths
+// that belong to none.
+func ClusterPaths(paths [][][2]float64, opts ClusterOptions) ([]PathCluster, []int, error) {
+	if opts.Epsilon <= 0 {
+		return nil, nil, fmt.Errorf("cluster epsilon must be positive, got %v", opts.Epsilon)
+	}
+	if opts.MinPoints <= 0 {
+		opts.MinPoints = 3
+	}
+	if opts.Distance == nil {
+		opts.Distance = FrechetDistance
+	}
+
+	n := len(paths)
+	distances := make([][]float64, n)
+	for i := range distances {
+		distances[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			d := opts.Distance(paths[i], paths[j])
+			distances[i][j], distances[j][i] = d, d
+		}
+	}
+	neighbours := func(i int) []int {
+		var found []int
+		for j := 0; j < n; j++ {
+			if distances[i][j] <= opts.Epsilon {
+				found = append(found, j)
+			}
+		}
+		return found
+	}
+
+	const (
+		unvisited = 0
+		noise     = -1
+	)
+	labels := make([]int, n)
+	var clusters []PathCluster
+	for i := 0; i < n; i++ {
+		if labels[i] != unvisited {
+			continue
+		}
+		seeds := neighbours(i)
+		if len(seeds) < opts.MinPoints {
+			labels[i] = noise
+			continue
+		}
+		clusters = append(clusters, PathCluster{})
+		label := len(clusters)
+		labels[i] = label
+		for k := 0; k < len(seeds); k++ {
+			j := seeds[k]
+			if labels[j] == noise {
+				// A border path: reachable, but not dense enough to expand from.
+				labels[j] = label
+			}
+			if labels[j] != unvisited {
+				continue
+			}
+			labels[j] = label
+			if more := neighbours(j); len(more) >= opts.MinPoints {
+				seeds = append(seeds, more...)
+			}
+		}
+	}
+
+	var outliers []int
+	for i, label := range labels {
+		if label == noise {
+			outliers = append(outliers, i)
+			continue
+		}
+		clusters[label-1].Members = append(clusters[label-1].Members, i)
+	}
+	for c := range clusters {
+		cluster := &clusters[c]
+		best := math.Inf(1)
+		for _, i := range cluster.Members {
+			sum := 0.0
+			for _, j := range cluster.Members {
+				sum += distances[i][j]
+			}
+			if sum < best {
+				best, cluster.Medoid = sum, i
+			}
+		}
+		cluster.Representative = CompressCentroids(paths[cluster.Medoid], opts.MaxPoints)
+	}
+	return clusters, outliers, nil
+}
@@ -0,0 +1,134 @@
+package geometry
+
+import (
+	"math"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+)
+
+func line(from, to [2]float64, n int) [][2]float64 {
+	points := make([][2]float64, n)
+	for i := range points {
+		f := float64(i) / float64(n-1)
+		points[i] = [2]float64{from[0] + f*(to[0]-from[0]), from[1] + f*(to[1]-from[1])}
+	}
+	return points
+}
+
+func reversePath(path [][2]float64) [][2]float64 {
+	reversed := make([][2]float64, len(path))
+	for i := range path {
+		reversed[i] = path[len(path)-1-i]
+	}
+	return reversed
+}
+
+func TestPathDistances(t *testing.T) {
+	a := [][2]float64{{0, 0}, {10, 0}, {20, 0}}
+	b := [][2]float64{{0, 1}, {10, 1}, {20, 1}}
+	tests := []struct {
+		name     string
+		distance PathDistance
+		a, b     [][2]float64
+		want     float64
+	}{
+		{"frechet parallel", FrechetDistance, a, b, 1},
+		{"hausdorff parallel", HausdorffDistance, a, b, 1},
+		{"dtw parallel", DTWDistance, a, b, 3},
+		{"frechet identical", FrechetDistance, a, a, 0},
+		{"dtw resampled", DTWDistance, a, [][2]float64{{0, 0}, {0, 0}, {10, 0}, {20, 0}, {20, 0}}, 0},
+		// Travelling the same line the other way round only matters to Fréchet.
+		{"frechet reversed", FrechetDistance, a, reversePath(a), 20},
+		{"hausdorff reversed", HausdorffDistance, a, reversePath(a), 0},
+		{"frechet both empty", FrechetDistance, nil, nil, 0},
+		{"frechet one empty", FrechetDistance, a, nil, math.Inf(1)},
+		{"hausdorff one empty", HausdorffDistance, nil, b, math.Inf(1)},
+		{"dtw one empty", DTWDistance, a, nil, math.Inf(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.distance(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 && got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if got, reverse := tt.distance(tt.a, tt.b), tt.distance(tt.b, tt.a); got != reverse {
+				t.Fatalf("not symmetric: %v and %v", got, reverse)
+			}
+		})
+	}
+}
+
+func TestFrechetDistance_Detour(t *testing.T) {
+	// A detour far from the other path dominates the Fréchet distance.
+	straight := line([2]float64{0, 50}, [2]float64{100, 50}, 11)
+	detour := append([][2]float64(nil), straight...)
+	detour[5] = [2]float64{50, 80}
+	if got := FrechetDistance(straight, detour); got != 30 {
+		t.Fatalf("FrechetDistance: got %v, want 30", got)
+	}
+	if got := HausdorffDistance(straight, detour); got != 30 {
+		t.Fatalf("HausdorffDistance: got %v, want 30", got)
+	}
+}
+
+func TestClusterPaths(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	jitter := func(path [][2]float64) [][2]float64 {
+		jittered := make([][2]float64, len(path))
+		for i, p := range path {
+			jittered[i] = [2]float64{p[0] + rng.Float64() - 0.5, p[1] + rng.Float64() - 0.5}
+		}
+		return jittered
+	}
+
+	// Two routes: left to right along the top, and bottom to top on the right. The
+	// last path is an outlier walking diagonally.
+	var paths [][][2]float64
+	for i := 0; i < 5; i++ {
+		paths = append(paths, jitter(line([2]float64{0, 20}, [2]float64{100, 20}, 30)))
+	}
+	for i := 0; i < 4; i++ {
+		paths = append(paths, jitter(line([2]float64{80, 100}, [2]float64{80, 0}, 25)))
+	}
+	paths = append(paths, line([2]float64{0, 0}, [2]float64{100, 100}, 20))
+	// The same top route walked right to left is a different route.
+	paths = append(paths, reversePath(paths[0]))
+
+	clusters, noise, err := ClusterPaths(paths, ClusterOptions{Epsilon: 5, MaxPoints: 4})
+	if err != nil {
+		t.Fatalf("ClusterPaths returned error: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2", len(clusters))
+	}
+	if !reflect.DeepEqual(clusters[0].Members, []int{0, 1, 2, 3, 4}) || !reflect.DeepEqual(clusters[1].Members, []int{5, 6, 7, 8}) {
+		t.Fatalf("cluster members: got %v and %v", clusters[0].Members, clusters[1].Members)
+	}
+	if !reflect.DeepEqual(noise, []int{9, 10}) {
+		t.Fatalf("noise: got %v, want [9 10]", noise)
+	}
+	for _, cluster := range clusters {
+		if len(cluster.Representative) > 4 {
+			t.Fatalf("representative not compressed: %d points", len(cluster.Representative))
+		}
+		if !reflect.DeepEqual(cluster.Representative, CompressCentroids(paths[cluster.Medoid], 4)) {
+			t.Fatalf("representative is not the compressed medoid")
+		}
+	}
+
+	// Hausdorff ignores direction, so the reversed path joins the top route.
+	clusters, noise, _ = ClusterPaths(paths, ClusterOptions{Epsilon: 5, Distance: HausdorffDistance})
+	if len(clusters) != 2 || len(clusters[0].Members) != 6 || !reflect.DeepEqual(noise, []int{9}) {
+		t.Fatalf("Hausdorff clustering: got %d clusters, noise %v", len(clusters), noise)
+	}
+}
+
+func TestClusterPaths_Errors(t *testing.T) {
+	if _, _, err := ClusterPaths(nil, ClusterOptions{}); err == nil {
+		t.Fatalf("expected error for missing epsilon")
+	}
+	clusters, noise, err := ClusterPaths(nil, ClusterOptions{Epsilon: 1})
+	if err != nil || clusters != nil || noise != nil {
+		t.Fatalf("no paths: got %v, %v, %v", clusters, noise, err)
+	}
+}
@@ -0,0 +1,199 @@
+package geometry
+
+import (
+	"container/heap"
+	"math"
+)
+
+// segmentDistance returns the distance from p to the segment ab.
+func segmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / lengthSquared
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
+
+// farthest returns the index and distance of the point between first and last that
+// deviates most from the segment joining them.
+func farthest(points [][2]float64, first, last int) (int, float64) {
+	index, distance := -1, 0.0
+	for i := first + 1; i < last; i++ {
+		if d := segmentDistance(points[i], points[first], points[last]); d > distance || index < 0 {
+			index, distance = i, d
+		}
+	}
+	return index, distance
+}
+
+// keepMarked returns the marked points in their original order.
+func keepMarked(points [][2]float64, keep []bool) [][2]float64 {
+	simplified := make([][2]float64, 0, len(points))
+	for i, k := range keep {
+		if k {
+			simplified = append(simplified, points[i])
+		}
+	}
+	return simplified
+}
+
+// SimplifyRDP simplifies a trajectory with the Ramer-Douglas-Peucker algorithm,
+// dropping points that deviate less than epsilon from the simplified path. The
+// first and last points are always kept, as are sharp turns.
+func SimplifyRDP(points [][2]float64, epsilon float64) [][2]float64 {
+	if len(points) <= 2 {
+		return points
+	}
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		span := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		index, distance := farthest(points, span[0], span[1])
+		if index < 0 || distance <= epsilon {
+			continue
+		}
+		keep[index] = true
+		stack = append(stack, [2]int{span[0], index}, [2]int{index, span[1]})
+	}
+	return keepMarked(points, keep)
+}
+
+type rdpSpan struct {
+	first, last, index int
+	distance           float64
+}
+
+type rdpQueue []rdpSpan
+
+func (q rdpQueue) Len() int           { return len(q) }
+func (q rdpQueue) Less(i, j int) bool { return q[i].distance > q[j].distance }
+func (q rdpQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *rdpQueue) Push(x any)        { *q = append(*q, x.(rdpSpan)) }
+func (q *rdpQueue) Pop() any {
+	old := *q
+	span := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return span
+}
+
+// SimplifyRDPMax is a Ramer-Douglas-Peucker variant that refines the path where it
+// deviates most first, so that it can stop after maxPoints points like
+// CompressCentroids. It stops when every remaining point is within epsilon or
+// maxPoints points were kept; maxPoints <= 0 means no limit and epsilon 0 keeps
+// refining until the limit.
+func SimplifyRDPMax(points [][2]float64, epsilon float64, maxPoints int) [][2]float64 {
+	if len(points) <= 2 {
+		return points
+	}
+	if maxPoints == 1 {
+		return points[:1]
+	}
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	kept := 2
+
+	queue := &rdpQueue{}
+	push := func(first, last int) {
+		if index, distance := farthest(points, first, last); index >= 0 {
+			heap.Push(queue, rdpSpan{first: first, last: last, index: index, distance: distance})
+		}
+	}
+	push(0, len(points)-1)
+	for queue.Len() > 0 && (maxPoints <= 0 || kept < maxPoints) {
+		span := heap.Pop(queue).(rdpSpan)
+		if span.distance <= epsilon {
+			break
+		}
+		keep[span.index] = true
+		kept++
+		push(span.first, span.index)
+		push(span.index, span.last)
+	}
+	return keepMarked(points, keep)
+}
+
+type vwVertex struct {
+	index   int
+	area    float64
+	version int
+}
+
+type vwQueue []vwVertex
+
+func (q vwQueue) Len() int { return len(q) }
+func (q vwQueue) Less(i, j int) bool {
+	if q[i].area == q[j].area {
+		return q[i].index < q[j].index
+	}
+	return q[i].area < q[j].area
+}
+func (q vwQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *vwQueue) Push(x any)   { *q = append(*q, x.(vwVertex)) }
+func (q *vwQueue) Pop() any {
+	old := *q
+	vertex := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return vertex
+}
+
+func triangleArea(a, b, c [2]float64) float64 {
+	return math.Abs((b[0]-a[0])*(c[1]-a[1])-(c[0]-a[0])*(b[1]-a[1])) / 2
+}
+
+// SimplifyVisvalingam simplifies a trajectory with the Visvalingam-Whyatt algorithm
+// down to targetCount points, repeatedly removing the point that forms the smallest
+// triangle with its neighbours. The first and last points are always kept. The
+// input is returned unchanged when targetCount <= 0 or it already has few enough
+// points.
+func SimplifyVisvalingam(points [][2]float64, targetCount int) [][2]float64 {
+	if targetCount <= 0 || len(points) <= targetCount {
+		return points
+	}
+	if targetCount == 1 {
+		return points[:1]
+	}
+
+	n := len(points)
+	previous := make([]int, n)
+	next := make([]int, n)
+	versions := make([]int, n)
+	keep := make([]bool, n)
+	queue := make(vwQueue, 0, n)
+	for i := range points {
+		previous[i], next[i], keep[i] = i-1, i+1, true
+		if i > 0 && i < n-1 {
+			queue = append(queue, vwVertex{index: i, area: triangleArea(points[i-1], points[i], points[i+1])})
+		}
+	}
+	heap.Init(&queue)
+
+	for remaining := n; remaining > targetCount && queue.Len() > 0; {
+		vertex := heap.Pop(&queue).(vwVertex)
+		if vertex.version != versions[vertex.index] {
+			continue
+		}
+		i := vertex.index
+		keep[i] = false
+		remaining--
+		p, q := previous[i], next[i]
+		next[p], previous[q] = q, p
+
+		// Neighbours never get a smaller area than the point just removed, so the
+		// removal order stays consistent with the visual importance of each point.
+		for _, j := range []int{p, q} {
+			if j == 0 || j == n-1 {
+				continue
+			}
+			versions[j]++
+			area := math.Max(triangleArea(points[previous[j]], points[j], points[next[j]]), vertex.area)
+			heap.Push(&queue, vwVertex{index: j, area: area, version: versions[j]})
+		}
+	}
+	return keepMarked(points, keep)
+}
@@ -0,0 +1,115 @@
+package geometry
+
+import (
+	"reflect"
+	"testing"
+)
+
+// zigzag is a path with two sharp turns and slight noise on the straight parts.
+var zigzag = [][2]float64{
+	{0, 0}, {10, 0.1}, {20, -0.1}, {30, 0}, // straight
+	{30, 10}, {30.1, 20}, {30, 30}, // turn up
+	{40, 30}, {50, 30.1}, {60, 30}, // turn right
+}
+
+func TestSimplifyRDP(t *testing.T) {
+	got := SimplifyRDP(zigzag, 1)
+	want := [][2]float64{{0, 0}, {30, 0}, {30, 30}, {60, 30}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SimplifyRDP(eps=1): got %v, want %v", got, want)
+	}
+
+	if got := SimplifyRDP(zigzag, 0); len(got) != len(zigzag) {
+		t.Fatalf("SimplifyRDP(eps=0): got %d points, want all %d", len(got), len(zigzag))
+	}
+	if got := SimplifyRDP(zigzag, 1000); !reflect.DeepEqual(got, [][2]float64{{0, 0}, {60, 30}}) {
+		t.Fatalf("SimplifyRDP(eps=1000): got %v", got)
+	}
+	short := [][2]float64{{0, 0}, {1, 1}}
+	if got := SimplifyRDP(short, 1); !reflect.DeepEqual(got, short) {
+		t.Fatalf("SimplifyRDP(2 points): got %v", got)
+	}
+}
+
+func TestSimplifyRDP_ClosedLoop(t *testing.T) {
+	// A path returning to its start: the distance to the segment, not the line, is used.
+	loop := [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	if got := SimplifyRDP(loop, 1); len(got) != 5 {
+		t.Fatalf("SimplifyRDP(loop): got %v, want all corners kept", got)
+	}
+}
+
+func TestSimplifyRDPMax(t *testing.T) {
+	// Corners are refined first, so three points keep the largest turn.
+	got := SimplifyRDPMax(zigzag, 0, 3)
+	if len(got) != 3 || got[0] != zigzag[0] || got[2] != zigzag[len(zigzag)-1] {
+		t.Fatalf("SimplifyRDPMax(max=3): got %v", got)
+	}
+	if got[1] != [2]float64{30, 0} && got[1] != [2]float64{30, 30} {
+		t.Fatalf("SimplifyRDPMax(max=3): middle point %v is not a corner", got[1])
+	}
+
+	if got := SimplifyRDPMax(zigzag, 0, 4); !reflect.DeepEqual(got, [][2]float64{{0, 0}, {30, 0}, {30, 30}, {60, 30}}) {
+		t.Fatalf("SimplifyRDPMax(max=4): got %v", got)
+	}
+	// Epsilon stops refinement before the limit.
+	if got := SimplifyRDPMax(zigzag, 1, 8); len(got) != 4 {
+		t.Fatalf("SimplifyRDPMax(eps=1, max=8): got %v, want 4 points", got)
+	}
+	if got := SimplifyRDPMax(zigzag, 0, 0); len(got) != len(zigzag) {
+		t.Fatalf("SimplifyRDPMax(no limits): got %d points", len(got))
+	}
+	if got := SimplifyRDPMax(zigzag, 0, 1); !reflect.DeepEqual(got, zigzag[:1]) {
+		t.Fatalf("SimplifyRDPMax(max=1): got %v", got)
+	}
+}
+
+func TestSimplifyVisvalingam(t *testing.T) {
+	got := SimplifyVisvalingam(zigzag, 4)
+	want := [][2]float64{{0, 0}, {30, 0}, {30, 30}, {60, 30}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SimplifyVisvalingam(4): got %v, want %v", got, want)
+	}
+
+	for target := 2; target <= len(zigzag); target++ {
+		got := SimplifyVisvalingam(zigzag, target)
+		if len(got) != target {
+			t.Fatalf("SimplifyVisvalingam(%d): got %d points", target, len(got))
+		}
+		if got[0] != zigzag[0] || got[len(got)-1] != zigzag[len(zigzag)-1] {
+			t.Fatalf("SimplifyVisvalingam(%d): endpoints not kept: %v", target, got)
+		}
+	}
+
+	if got := SimplifyVisvalingam(zigzag, 0); !reflect.DeepEqual(got, zigzag) {
+		t.Fatalf("SimplifyVisvalingam(0): got %v", got)
+	}
+	if got := SimplifyVisvalingam(zigzag, 1); !reflect.DeepEqual(got, zigzag[:1]) {
+		t.Fatalf("SimplifyVisvalingam(1): got %v", got)
+	}
+}
+
+func TestSimplifyKeepsTurnsThatCompressCentroidsLoses(t *testing.T) {
+	// A long straight run followed by a short sharp detour and a return.
+	var path [][2]float64
+	for i := 0; i <= 90; i++ {
+		path = append(path, [2]float64{float64(i), 50})
+	}
+	path = append(path, [2]float64{92, 58}, [2]float64{94, 50}, [2]float64{100, 50})
+
+	detour := [2]float64{92, 58}
+	has := func(points [][2]float64) bool {
+		for _, p := range points {
+			if p == detour {
+				return true
+			}
+		}
+		return false
+	}
+	if !has(SimplifyRDPMax(path, 0, 5)) {
+		t.Fatalf("SimplifyRDPMax lost the detour")
+	}
+	if !has(SimplifyVisvalingam(path, 5)) {
+		t.Fatalf("SimplifyVisvalingam lost the detour")
+	}
+}
@@ -0,0 +1,207 @@
+package geometry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGStyle holds the presentation attributes of an SVG element. Empty fields are
+// left out, so the SVG defaults apply.
+type SVGStyle struct {
+	Stroke      string
+	StrokeWidth float64
+	Fill        string
+	Opacity     float64
+}
+
+// SVGTrajectoryOptions configures how SVGCanvas.AddTrajectory draws a path.
+type SVGTrajectoryOptions struct {
+	Style SVGStyle
+	// Arrow ends the path with an arrowhead showing the direction of travel.
+	Arrow bool
+	// GradientFrom and GradientTo are "#rrggbb" colors. When both are set, the
+	// path is drawn segment by segment fading from the first to the second color,
+	// so the direction of travel is visible over time, and Style.Stroke is ignored.
+	GradientFrom string
+	GradientTo   string
+}
+
+// SVGCanvas collects trajectories, boxes and polygons and renders them as an SVG
+// document, e.g. to overlay CompressCentroids output on a snapshot thumbnail. The
+// canvas uses a viewBox of Width x Height that stretches to the size of the
+// image it is drawn on, so the default of 100 x 100 takes the normalized
+// centroids of BuildCentroids as they are.
+type SVGCanvas struct {
+	Width    float64
+	Height   float64
+	elements []string
+	markers  []string
+}
+
+// NewSVGCanvas returns an empty canvas of the given size, or 100 x 100 when the size
+// is not positive.
+func NewSVGCanvas(width, height float64) *SVGCanvas {
+	if width <= 0 || height <= 0 {
+		width, height = 100, 100
+	}
+	return &SVGCanvas{Width: width, Height: height}
+}
+
+// svgNumber formats a coordinate with at most two decimals.
+func svgNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func (s SVGStyle) attributes(defaultFill string) string {
+	var b strings.Builder
+	if s.Stroke != "" {
+		fmt.Fprintf(&b, ` stroke="%s"`, escapeSVG(s.Stroke))
+	}
+	if s.StrokeWidth > 0 {
+		fmt.Fprintf(&b, ` stroke-width="%s"`, svgNumber(s.StrokeWidth))
+	}
+	fill := s.Fill
+	if fill == "" {
+		fill = defaultFill
+	}
+	if fill != "" {
+		fmt.Fprintf(&b, ` fill="%s"`, escapeSVG(fill))
+	}
+	if s.Opacity > 0 && s.Opacity < 1 {
+		fmt.Fprintf(&b, ` opacity="%s"`, svgNumber(s.Opacity))
+	}
+	return b.String()
+}
+
+func escapeSVG(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;").Replace(s)
+}
+
+func svgPoints(points [][2]float64) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = svgNumber(p[0]) + "," + svgNumber(p[1])
+	}
+	return strings.Join(parts, " ")
+}
+
+// parseHexColor parses a "#rrggbb" color.
+func parseHexColor(s string) ([3]uint8, error) {
+	var rgb [3]uint8
+	if len(s) != 7 || s[0] != '#' {
+		return rgb, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+	}
+	for i := range rgb {
+		v, err := strconv.ParseUint(s[1+2*i:3+2*i], 16, 8)
+		if err != nil {
+			return rgb, fmt.Errorf("invalid color %q, expected #rrggbb", s)
+		}
+		rgb[i] = uint8(v)
+	}
+	return rgb, nil
+}
+
+// arrowMarker adds an arrowhead marker in the given color and returns its URL.
+func (c *SVGCanvas) arrowMarker(color string) string {
+	if color == "" {
+		color = "black"
+	}
+	id := fmt.Sprintf("arrow-%d", len(c.markers)+1)
+	c.markers = append(c.markers, fmt.Sprintf(
+		`<marker id="%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="4" markerHeight="4" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`,
+		id, escapeSVG(color)))
+	return "url(#" + id + ")"
+}
+
+// AddTrajectory draws a path through centroids as a polyline.
+func (c *SVGCanvas) AddTrajectory(centroids [][2]float64, opts SVGTrajectoryOptions) error {
+	if len(centroids) == 0 {
+		return nil
+	}
+	if opts.GradientFrom == "" || opts.GradientTo == "" || len(centroids) < 2 {
+		marker := ""
+		if opts.Arrow && len(centroids) > 1 {
+			marker = fmt.Sprintf(` marker-end="%s"`, c.arrowMarker(opts.Style.Stroke))
+		}
+		c.elements = append(c.elements, fmt.Sprintf(`<polyline points="%s"%s%s/>`,
+			svgPoints(centroids), opts.Style.attributes("none"), marker))
+		return nil
+	}
+
+	from, err := parseHexColor(opts.GradientFrom)
+	if err != nil {
+		return err
+	}
+	to, err := parseHexColor(opts.GradientTo)
+	if err != nil {
+		return err
+	}
+	segments := len(centroids) - 1
+	var group strings.Builder
+	group.WriteString(`<g stroke-linecap="round">`)
+	for i := 0; i < segments; i++ {
+		f := 0.0
+		if segments > 1 {
+			f = float64(i) / float64(segments-1)
+		}
+		var color [3]uint8
+		for k := range color {
+			color[k] = uint8(math.Round(float64(from[k]) + f*(float64(to[k])-float64(from[k]))))
+		}
+		style := opts.Style
+		style.Stroke = fmt.Sprintf("#%02x%02x%02x", color[0], color[1], color[2])
+		marker := ""
+		if opts.Arrow && i == segments-1 {
+			marker = fmt.Sprintf(` marker-end="%s"`, c.arrowMarker(style.Stroke))
+		}
+		a, b := centroids[i], centroids[i+1]
+		fmt.Fprintf(&group, `<line x1="%s" y1="%s" x2="%s" y2="%s"%s%s/>`,
+			svgNumber(a[0]), svgNumber(a[1]), svgNumber(b[0]), svgNumber(b[1]), style.attributes(""), marker)
+	}
+	group.WriteString(`</g>`)
+	c.elements = append(c.elements, group.String())
+	return nil
+}
+
+// AddBox draws a box as a rectangle.
+func (c *SVGCanvas) AddBox(b BBox, style SVGStyle) {
+	c.elements = append(c.elements, fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"%s/>`,
+		svgNumber(b.X1), svgNumber(b.Y1), svgNumber(b.Width()), svgNumber(b.Height()), style.attributes("none")))
+}
+
+// AddPolygon draws a polygon, e.g. a zone.
+func (c *SVGCanvas) AddPolygon(p Polygon, style SVGStyle) {
+	points := make([][2]float64, len(p))
+	for i, v := range p {
+		points[i] = v.Array()
+	}
+	c.elements = append(c.elements, fmt.Sprintf(`<polygon points="%s"%s/>`, svgPoints(points), style.attributes("none")))
+}
+
+// String renders the canvas as an SVG document.
+func (c *SVGCanvas) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %s %s" preserveAspectRatio="none">`+"\n",
+		svgNumber(c.Width), svgNumber(c.Height))
+	if len(c.markers) > 0 {
+		b.WriteString("<defs>\n")
+		for _, m := range c.markers {
+			b.WriteString(m + "\n")
+		}
+		b.WriteString("</defs>\n")
+	}
+	for _, e := range c.elements {
+		b.WriteString(e + "\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// WriteTo writes the SVG document to w.
+func (c *SVGCanvas) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, c.String())
+	return int64(n), err
+}
@@ -0,0 +1,73 @@
+package geometry
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+// assertGolden compares got with the golden file testdata/name, rewriting the file
+// when the tests run with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s does not match the golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+// goldenPath is a walk through a scene, as returned by CompressCentroids.
+var goldenPath = [][2]float64{{10, 80}, {25, 60}, {40, 55.555}, {70, 30}, {90, 20}}
+
+func TestSVGCanvas_Golden(t *testing.T) {
+	canvas := NewSVGCanvas(0, 0)
+	canvas.AddPolygon(NewPolygon([][2]float64{{50, 10}, {95, 10}, {95, 45}, {50, 45}}),
+		SVGStyle{Stroke: "#ffcc00", StrokeWidth: 0.5, Fill: "#ffcc00", Opacity: 0.25})
+	canvas.AddBox(NewBBoxXYWH(85, 12, 10, 16), SVGStyle{Stroke: "red", StrokeWidth: 0.4})
+	if err := canvas.AddTrajectory(goldenPath, SVGTrajectoryOptions{Style: SVGStyle{Stroke: "#00a0ff", StrokeWidth: 1}, Arrow: true}); err != nil {
+		t.Fatalf("AddTrajectory returned error: %v", err)
+	}
+	assertGolden(t, "trajectory.svg", []byte(canvas.String()))
+}
+
+func TestSVGCanvas_GradientGolden(t *testing.T) {
+	canvas := NewSVGCanvas(1920, 1080)
+	frame := NormalizedToFrame(1920, 1080).ApplyCentroids(goldenPath)
+	opts := SVGTrajectoryOptions{Style: SVGStyle{StrokeWidth: 6}, Arrow: true, GradientFrom: "#0000ff", GradientTo: "#ff0000"}
+	if err := canvas.AddTrajectory(frame, opts); err != nil {
+		t.Fatalf("AddTrajectory returned error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if _, err := canvas.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo returned error: %v", err)
+	}
+	assertGolden(t, "trajectory_gradient.svg", buf.Bytes())
+}
+
+func TestSVGCanvas_Errors(t *testing.T) {
+	canvas := NewSVGCanvas(100, 100)
+	opts := SVGTrajectoryOptions{GradientFrom: "blue", GradientTo: "#ff0000"}
+	if err := canvas.AddTrajectory(goldenPath, opts); err == nil {
+		t.Fatalf("expected error for invalid gradient color")
+	}
+	if err := canvas.AddTrajectory(nil, SVGTrajectoryOptions{}); err != nil {
+		t.Fatalf("AddTrajectory(nil) returned error: %v", err)
+	}
+	if got := canvas.String(); bytes.Contains([]byte(got), []byte("<polyline")) || bytes.Contains([]byte(got), []byte("<line")) {
+		t.Fatalf("failed trajectories should not be drawn: %s", got)
+	}
+}
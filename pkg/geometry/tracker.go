@@ -0,0 +1,272 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+)
+
+// TrackerOptions configures a Tracker.
+type TrackerOptions struct {
+	// IoUThreshold is the minimum overlap between a track and a detection to match
+	// them. Defaults to 0.3.
+	IoUThreshold float64
+	// MaxAge is the number of consecutive frames a track survives without a match.
+	// Defaults to 5.
+	MaxAge int
+	// MinHits is the number of matched frames after which a track is reported.
+	// Defaults to 1.
+	MinHits int
+	// PredictVelocity matches detections against the box extrapolated with the
+	// track's velocity instead of its last box, which helps with fast objects.
+	PredictVelocity bool
+}
+
+// Track is the path of one object through consecutive frames. Timestamps holds the
+// time of each box in Unix milliseconds, as passed to Tracker.Update.
+type Track struct {
+	ID         int     `json:"id" bson:"id"`
+	Class      string  `json:"class,omitempty" bson:"class,omitempty"`
+	Boxes      []BBox  `json:"boxes" bson:"boxes"`
+	Timestamps []int64 `json:"timestamps" bson:"timestamps"`
+
+	// velocity is the smoothed change of the box coordinates per frame.
+	velocity [4]float64
+	// lastFrame is the frame in which the track was last matched.
+	lastFrame int
+}
+
+// LastBox returns the most recent box of the track.
+func (t *Track) LastBox() BBox {
+	return t.Boxes[len(t.Boxes)-1]
+}
+
+// Traject returns the boxes of the track in the traject format used by
+// BuildCentroids.
+func (t *Track) Traject() []interface{} {
+	return BoxesToTraject(t.Boxes)
+}
+
+// Centroids returns the centroids of the track normalized like BuildCentroids does,
+// ready for CompressCentroids.
+func (t *Track) Centroids(frameWidth, frameHeight float64) [][2]float64 {
+	return BuildCentroids(t.Traject(), frameWidth, frameHeight)
+}
+
+// Trajectory returns the normalized centroids of the track with their timestamps
+// in Unix milliseconds.
+func (t *Track) Trajectory(frameWidth, frameHeight float64) (Trajectory, error) {
+	return NewTrajectory(t.Centroids(frameWidth, frameHeight), t.Timestamps)
+}
+
+// predict returns where the track's box is expected in frame.
+func (t *Track) predict(frame int, useVelocity bool) BBox {
+	last := t.LastBox()
+	if !useVelocity {
+		return last
+	}
+	steps := float64(frame - t.lastFrame)
+	return NewBBoxXYXY(
+		last.X1+t.velocity[0]*steps,
+		last.Y1+t.velocity[1]*steps,
+		last.X2+t.velocity[2]*steps,
+		last.Y2+t.velocity[3]*steps,
+	)
+}
+
+// add appends a matched box and updates the velocity estimate.
+func (t *Track) add(frame int, timestamp int64, box BBox) {
+	if len(t.Boxes) > 0 {
+		last := t.LastBox()
+		steps := float64(frame - t.lastFrame)
+		delta := [4]float64{box.X1 - last.X1, box.Y1 - last.Y1, box.X2 - last.X2, box.Y2 - last.Y2}
+		for i := range delta {
+			if len(t.Boxes) == 1 {
+				t.velocity[i] = delta[i] / steps
+			} else {
+				t.velocity[i] = (t.velocity[i] + delta[i]/steps) / 2
+			}
+		}
+	}
+	t.Boxes = append(t.Boxes, box)
+	t.Timestamps = append(t.Timestamps, timestamp)
+	t.lastFrame = frame
+}
+
+// Tracker assigns detections from consecutive frames to tracks, for recordings
+// without track IDs. Detections are matched to tracks of the same class by IoU
+// with the Hungarian algorithm; unmatched detections start new tracks.
+type Tracker struct {
+	opts     TrackerOptions
+	frame    int
+	nextID   int
+	active   []*Track
+	finished []*Track
+}
+
+// NewTracker returns a tracker with the given options.
+func NewTracker(opts TrackerOptions) *Tracker {
+	if opts.IoUThreshold <= 0 {
+		opts.IoUThreshold = 0.3
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = 5
+	}
+	if opts.MinHits <= 0 {
+		opts.MinHits = 1
+	}
+	return &Tracker{opts: opts, nextID: 1}
+}
+
+// Update processes the detections of the next frame, taken at timestamp in Unix
+// milliseconds, and returns the reported tracks that were matched or started in it.
+// Use milliseconds rather than the Unix seconds of pkg/date, as Track.Trajectory
+// computes speeds and durations from them.
+func (t *Tracker) Update(timestamp int64, detections []Detection) []*Track {
+	t.frame++
+
+	cost := make([][]float64, len(t.active))
+	for i, track := range t.active {
+		predicted := track.predict(t.frame, t.opts.PredictVelocity)
+		cost[i] = make([]float64, len(detections))
+		for j, d := range detections {
+			cost[i][j] = 1
+			if d.Class == track.Class {
+				cost[i][j] = 1 - IoU(predicted, d.Box)
+			}
+		}
+	}
+
+	matched := make([]bool, len(detections))
+	var updated []*Track
+	for i, j := range hungarian(cost, len(detections)) {
+		if j < 0 || 1-cost[i][j] < t.opts.IoUThreshold {
+			continue
+		}
+		track := t.active[i]
+		track.add(t.frame, timestamp, detections[j].Box)
+		matched[j] = true
+		updated = append(updated, track)
+	}
+	for j, d := range detections {
+		if matched[j] {
+			continue
+		}
+		track := &Track{ID: t.nextID, Class: d.Class}
+		t.nextID++
+		track.add(t.frame, timestamp, d.Box)
+		t.active = append(t.active, track)
+		updated = append(updated, track)
+	}
+
+	active := t.active[:0]
+	for _, track := range t.active {
+		switch {
+		case t.frame-track.lastFrame <= t.opts.MaxAge:
+			active = append(active, track)
+		case len(track.Boxes) >= t.opts.MinHits:
+			t.finished = append(t.finished, track)
+		}
+	}
+	t.active = active
+
+	return t.confirmed(updated)
+}
+
+// Active returns the reported tracks that are still being followed.
+func (t *Tracker) Active() []*Track {
+	return t.confirmed(t.active)
+}
+
+// Tracks returns every reported track, finished or not, ordered by ID.
+func (t *Tracker) Tracks() []*Track {
+	tracks := append(append([]*Track(nil), t.finished...), t.Active()...)
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].ID < tracks[j].ID })
+	return tracks
+}
+
+func (t *Tracker) confirmed(tracks []*Track) []*Track {
+	var confirmed []*Track
+	for _, track := range tracks {
+		if len(track.Boxes) >= t.opts.MinHits {
+			confirmed = append(confirmed, track)
+		}
+	}
+	sort.Slice(confirmed, func(i, j int) bool { return confirmed[i].ID < confirmed[j].ID })
+	return confirmed
+}
+
+// hungarian solves the assignment problem for a rows x columns cost matrix and
+// returns the column assigned to each row, or -1 when the row is left unassigned
+// because there are more rows than columns.
+func hungarian(cost [][]float64, columns int) []int {
+	rows := len(cost)
+	n := max(rows, columns)
+	assignment := make([]int, rows)
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	if rows == 0 || columns == 0 {
+		return assignment
+	}
+	// Pad to a square matrix; padding costs nothing so it does not affect the
+	// optimal assignment of the real entries.
+	at := func(i, j int) float64 {
+		if i < rows && j < columns {
+			return cost[i][j]
+		}
+		return 0
+	}
+
+	// Potentials u and v, with 1-based indices and index 0 as a sentinel.
+	u := make([]float64, n+1)
+	v := make([]float64, n+1)
+	p := make([]int, n+1)
+	way := make([]int, n+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		minimum := make([]float64, n+1)
+		used := make([]bool, n+1)
+		for j := range minimum {
+			minimum[j] = math.Inf(1)
+		}
+		for {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= n; j++ {
+				if used[j] {
+					continue
+				}
+				if reduced := at(i0-1, j-1) - u[i0] - v[j]; reduced < minimum[j] {
+					minimum[j], way[j] = reduced, j0
+				}
+				if minimum[j] < delta {
+					delta, j1 = minimum[j], j
+				}
+			}
+			for j := 0; j <= n; j++ {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minimum[j] -= delta
+				}
+			}
+			j0 = j1
+			if p[j0] == 0 {
+				break
+			}
+		}
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	for j := 1; j <= n; j++ {
+		if i := p[j] - 1; i < rows && j-1 < columns {
+			assignment[i] = j - 1
+		}
+	}
+	return assignment
+}
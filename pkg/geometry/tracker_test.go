@@ -0,0 +1,140 @@
+package geometry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHungarian(t *testing.T) {
+	tests := []struct {
+		name    string
+		cost    [][]float64
+		columns int
+		want    []int
+	}{
+		{"empty", nil, 0, []int{}},
+		{"no columns", [][]float64{{}, {}}, 0, []int{-1, -1}},
+		// Greedy would take (0,0) at cost 1 and leave (1,1) at cost 10.
+		{"global optimum", [][]float64{{1, 2}, {2, 10}}, 2, []int{1, 0}},
+		{"more columns", [][]float64{{5, 1, 3}}, 3, []int{1}},
+		{"more rows", [][]float64{{5}, {1}, {3}}, 1, []int{-1, 0, -1}},
+		{"three by three", [][]float64{{4, 1, 3}, {2, 0, 5}, {3, 2, 2}}, 3, []int{1, 0, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hungarian(tt.cost, tt.columns)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("hungarian(%v): got %v, want %v", tt.cost, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracker_TwoObjects(t *testing.T) {
+	tracker := NewTracker(TrackerOptions{})
+	// Two people walking towards each other on separate rows.
+	for frame := 0; frame < 10; frame++ {
+		x := float64(frame * 10)
+		detections := []Detection{
+			{Box: NewBBoxXYWH(300-x, 200, 40, 100), Class: "person"},
+			{Box: NewBBoxXYWH(x, 50, 40, 100), Class: "person"},
+		}
+		tracker.Update(int64(frame)*100, detections)
+	}
+
+	tracks := tracker.Tracks()
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(tracks))
+	}
+	for _, track := range tracks {
+		if len(track.Boxes) != 10 || len(track.Timestamps) != 10 {
+			t.Fatalf("track %d: got %d boxes, want 10", track.ID, len(track.Boxes))
+		}
+		for _, box := range track.Boxes[1:] {
+			if box.Y1 != track.Boxes[0].Y1 {
+				t.Fatalf("track %d switched objects: %v", track.ID, track.Boxes)
+			}
+		}
+	}
+
+	// The output feeds the existing centroid pipeline.
+	centroids := BuildCentroids(tracks[1].Traject(), 400, 300)
+	if len(centroids) != 10 || centroids[0] != [2]float64{5, 100.0 / 3} {
+		t.Fatalf("BuildCentroids of track: got %v", centroids)
+	}
+	if got := CompressCentroids(tracks[1].Centroids(400, 300), 3); len(got) != 3 {
+		t.Fatalf("CompressCentroids of track: got %v", got)
+	}
+	if trajectory, err := tracks[1].Trajectory(400, 300); err != nil || trajectory.Duration().Milliseconds() != 900 {
+		t.Fatalf("Trajectory of track: got %v, %v", trajectory, err)
+	}
+}
+
+func TestTracker_VelocityPrediction(t *testing.T) {
+	// An object accelerates to 30 pixels per frame with a 40 pixel wide box, so
+	// once it is fast consecutive boxes overlap with IoU 0.14, below the threshold.
+	positions := []float64{0, 10, 30, 55, 85, 115, 145, 175}
+	run := func(predict bool) int {
+		tracker := NewTracker(TrackerOptions{PredictVelocity: predict})
+		for frame, x := range positions {
+			tracker.Update(int64(frame), []Detection{{Box: NewBBoxXYWH(x, 0, 40, 40)}})
+		}
+		return len(tracker.Tracks())
+	}
+	if got := run(false); got <= 1 {
+		t.Fatalf("without prediction: got %d tracks, want the object to be lost", got)
+	}
+	if got := run(true); got != 1 {
+		t.Fatalf("with prediction: got %d tracks, want 1", got)
+	}
+}
+
+func TestTracker_MaxAgeAndMinHits(t *testing.T) {
+	tracker := NewTracker(TrackerOptions{MaxAge: 2, MinHits: 2})
+	box := NewBBoxXYWH(10, 10, 50, 50)
+
+	if got := tracker.Update(0, []Detection{{Box: box}, {Box: NewBBoxXYWH(200, 200, 10, 10)}}); len(got) != 0 {
+		t.Fatalf("tracks reported before MinHits: %v", got)
+	}
+	if got := tracker.Update(1, []Detection{{Box: box}}); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("confirmed track not reported: %v", got)
+	}
+	// The object is hidden for two frames and keeps its ID.
+	tracker.Update(2, nil)
+	tracker.Update(3, nil)
+	if got := tracker.Update(4, []Detection{{Box: box}}); len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("track not resumed after occlusion: %v", got)
+	}
+	// Hidden for three frames, it is finished and a new track starts.
+	for frame := int64(5); frame < 8; frame++ {
+		tracker.Update(frame, nil)
+	}
+	if active := tracker.Active(); len(active) != 0 {
+		t.Fatalf("track still active after MaxAge: %v", active)
+	}
+	tracker.Update(8, []Detection{{Box: box}})
+	tracker.Update(9, []Detection{{Box: box}})
+
+	tracks := tracker.Tracks()
+	if len(tracks) != 2 || tracks[0].ID != 1 || tracks[1].ID != 3 {
+		t.Fatalf("Tracks: got %d tracks", len(tracks))
+	}
+	if !reflect.DeepEqual(tracks[0].Timestamps, []int64{0, 1, 4}) {
+		t.Fatalf("finished track timestamps: got %v", tracks[0].Timestamps)
+	}
+}
+
+func TestTracker_Classes(t *testing.T) {
+	tracker := NewTracker(TrackerOptions{})
+	box := NewBBoxXYWH(10, 10, 50, 50)
+	tracker.Update(0, []Detection{{Box: box, Class: "car"}})
+	tracker.Update(1, []Detection{{Box: box, Class: "person"}})
+
+	tracks := tracker.Tracks()
+	if len(tracks) != 2 || tracks[0].Class != "car" || tracks[1].Class != "person" {
+		t.Fatalf("classes should not share tracks: got %d tracks", len(tracks))
+	}
+}
@@ -0,0 +1,110 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// MalformedEntry describes a traject entry that could not be decoded.
+type MalformedEntry struct {
+	Index  int
+	Reason string
+}
+
+// TrajectError lists the malformed entries found by DecodeTraject.
+type TrajectError struct {
+	Entries []MalformedEntry
+}
+
+func (e *TrajectError) Error() string {
+	parts := make([]string, 0, len(e.Entries))
+	for _, entry := range e.Entries {
+		parts = append(parts, fmt.Sprintf("entry %d: %s", entry.Index, entry.Reason))
+	}
+	return fmt.Sprintf("geometry: %d malformed traject entries: %s", len(e.Entries), strings.Join(parts, "; "))
+}
+
+// DecodeTraject decodes a traject, a list of [x1, y1, x2, y2, ...] boxes as consumed
+// by BuildCentroids, into typed boxes. Unlike BuildCentroids, which silently skips
+// entries it cannot read, it returns a *TrajectError listing every malformed entry
+// together with the boxes that could be decoded. Extra elements after the four
+// coordinates are ignored.
+func DecodeTraject(traject []interface{}) ([]BBox, error) {
+	boxes := make([]BBox, 0, len(traject))
+	var malformed []MalformedEntry
+	for i, t := range traject {
+		var coord []interface{}
+		switch cast := t.(type) {
+		case []interface{}:
+			coord = cast
+		case bson.A:
+			coord = cast
+		case []float64:
+			coord = make([]interface{}, len(cast))
+			for j, v := range cast {
+				coord[j] = v
+			}
+		default:
+			malformed = append(malformed, MalformedEntry{Index: i, Reason: fmt.Sprintf("expected an array, got %T", t)})
+			continue
+		}
+		if len(coord) < 4 {
+			malformed = append(malformed, MalformedEntry{Index: i, Reason: fmt.Sprintf("expected 4 coordinates, got %d", len(coord))})
+			continue
+		}
+
+		var values [4]float64
+		reason := ""
+		for j := range values {
+			v, ok := toFloat(coord[j])
+			if !ok {
+				reason = fmt.Sprintf("coordinate %d is %T, not a number", j, coord[j])
+				break
+			}
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				reason = fmt.Sprintf("coordinate %d is not finite", j)
+				break
+			}
+			values[j] = v
+		}
+		if reason != "" {
+			malformed = append(malformed, MalformedEntry{Index: i, Reason: reason})
+			continue
+		}
+		boxes = append(boxes, NewBBoxXYXY(values[0], values[1], values[2], values[3]))
+	}
+
+	if len(malformed) > 0 {
+		return boxes, &TrajectError{Entries: malformed}
+	}
+	return boxes, nil
+}
+
+// BoxesToTraject converts boxes back into the traject format consumed by
+// BuildCentroids.
+func BoxesToTraject(boxes []BBox) []interface{} {
+	traject := make([]interface{}, 0, len(boxes))
+	for _, b := range boxes {
+		traject = append(traject, []interface{}{b.X1, b.Y1, b.X2, b.Y2})
+	}
+	return traject
+}
+
+// CentroidsFromBoxes returns the box centers normalized to a 100x100 space, exactly
+// like BuildCentroids does for a traject. Centers are kept raw when the frame size
+// is unknown.
+func CentroidsFromBoxes(boxes []BBox, frameWidth, frameHeight float64) [][2]float64 {
+	centroids := make([][2]float64, 0, len(boxes))
+	for _, b := range boxes {
+		c := b.Center()
+		if frameWidth > 0 && frameHeight > 0 {
+			c.X = c.X * 100.0 / frameWidth
+			c.Y = c.Y * 100.0 / frameHeight
+		}
+		centroids = append(centroids, c.Array())
+	}
+	return centroids
+}
@@ -0,0 +1,82 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestDecodeTraject_Valid(t *testing.T) {
+	var traject []interface{}
+	if err := json.Unmarshal([]byte(`[[0,0,10,10],[10,20,30,40,0.9]]`), &traject); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	traject = append(traject, bson.A{int32(5), int64(5), 15.0, 25.0}, []float64{1, 1, 3, 3})
+
+	boxes, err := DecodeTraject(traject)
+	if err != nil {
+		t.Fatalf("DecodeTraject: unexpected error %v", err)
+	}
+	want := []BBox{{0, 0, 10, 10}, {10, 20, 30, 40}, {5, 5, 15, 25}, {1, 1, 3, 3}}
+	if !reflect.DeepEqual(boxes, want) {
+		t.Fatalf("DecodeTraject: got %v, want %v", boxes, want)
+	}
+}
+
+func TestDecodeTraject_ReportsMalformed(t *testing.T) {
+	traject := []interface{}{
+		[]interface{}{0.0, 0.0, 10.0, 10.0},
+		"not an array",
+		[]interface{}{1.0, 2.0, 3.0},
+		[]interface{}{1.0, "2", 3.0, 4.0},
+		[]interface{}{1.0, math.NaN(), 3.0, 4.0},
+		[]interface{}{20.0, 20.0, 40.0, 40.0},
+	}
+
+	boxes, err := DecodeTraject(traject)
+	var trajectErr *TrajectError
+	if !errors.As(err, &trajectErr) {
+		t.Fatalf("DecodeTraject: got error %v, want *TrajectError", err)
+	}
+	indices := make([]int, 0, len(trajectErr.Entries))
+	for _, entry := range trajectErr.Entries {
+		indices = append(indices, entry.Index)
+	}
+	if !reflect.DeepEqual(indices, []int{1, 2, 3, 4}) {
+		t.Fatalf("malformed indices: got %v, want [1 2 3 4]", indices)
+	}
+	if !strings.Contains(err.Error(), "entry 3: coordinate 1 is string") {
+		t.Fatalf("error message does not explain entry 3: %q", err.Error())
+	}
+	if !reflect.DeepEqual(boxes, []BBox{{0, 0, 10, 10}, {20, 20, 40, 40}}) {
+		t.Fatalf("valid boxes: got %v", boxes)
+	}
+}
+
+func TestCentroidsFromBoxesMatchesBuildCentroids(t *testing.T) {
+	traject := []interface{}{
+		[]interface{}{0.0, 0.0, 100.0, 50.0},
+		[]interface{}{320.0, 180.0, 640.0, 360.0},
+	}
+	boxes, err := DecodeTraject(traject)
+	if err != nil {
+		t.Fatalf("DecodeTraject: %v", err)
+	}
+
+	for _, size := range [][2]float64{{640, 360}, {0, 0}} {
+		got := CentroidsFromBoxes(boxes, size[0], size[1])
+		want := BuildCentroids(traject, size[0], size[1])
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("frame %v: CentroidsFromBoxes=%v, BuildCentroids=%v", size, got, want)
+		}
+	}
+
+	if got := BuildCentroids(BoxesToTraject(boxes), 640, 360); !reflect.DeepEqual(got, CentroidsFromBoxes(boxes, 640, 360)) {
+		t.Fatalf("BoxesToTraject round trip: got %v", got)
+	}
+}
@@ -0,0 +1,344 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Direction is one of the eight compass directions on screen, with y pointing down.
+type Direction string
+
+const (
+	DirectionRight     Direction = "right"
+	DirectionDownRight Direction = "down-right"
+	DirectionDown      Direction = "down"
+	DirectionDownLeft  Direction = "down-left"
+	DirectionLeft      Direction = "left"
+	DirectionUpLeft    Direction = "up-left"
+	DirectionUp        Direction = "up"
+	DirectionUpRight   Direction = "up-right"
+)
+
+// directions are ordered by heading, starting at 0 degrees.
+var directions = [8]Direction{
+	DirectionRight, DirectionDownRight, DirectionDown, DirectionDownLeft,
+	DirectionLeft, DirectionUpLeft, DirectionUp, DirectionUpRight,
+}
+
+// Trajectory is the path of a single object: its centroids, as returned by
+// BuildCentroids, and the time of each of them in Unix milliseconds. Methods that
+// need the time of each point return zero or empty results when the number of
+// timestamps does not match the number of centroids, e.g. for a decoded value that
+// did not go through NewTrajectory.
+type Trajectory struct {
+	Centroids  [][2]float64 `json:"centroids" bson:"centroids"`
+	Timestamps []int64      `json:"timestamps" bson:"timestamps"`
+}
+
+// Stop is a part of a trajectory during which the object stayed in place.
+type Stop struct {
+	// StartIndex and EndIndex are the first and last trajectory index of the stop.
+	StartIndex int `json:"startIndex" bson:"startIndex"`
+	EndIndex   int `json:"endIndex" bson:"endIndex"`
+	// Start and End are the timestamps of the first and last point of the stop.
+	Start int64 `json:"start" bson:"start"`
+	End   int64 `json:"end" bson:"end"`
+	// Position is the mean position during the stop.
+	Position Point `json:"position" bson:"position"`
+}
+
+// Duration returns how long the stop lasted.
+func (s Stop) Duration() time.Duration {
+	return time.Duration(s.End-s.Start) * time.Millisecond
+}
+
+// NewTrajectory returns a trajectory after checking that there is one timestamp per
+// centroid and that the timestamps do not go back in time.
+func NewTrajectory(centroids [][2]float64, timestamps []int64) (Trajectory, error) {
+	if len(centroids) != len(timestamps) {
+		return Trajectory{}, fmt.Errorf("got %d timestamps for %d centroids", len(timestamps), len(centroids))
+	}
+	for i := 1; i < len(timestamps); i++ {
+		if timestamps[i] < timestamps[i-1] {
+			return Trajectory{}, fmt.Errorf("timestamp %d at index %d is before the previous one", timestamps[i], i)
+		}
+	}
+	return Trajectory{Centroids: centroids, Timestamps: timestamps}, nil
+}
+
+// TrajectoryFromTraject builds a trajectory from a traject and the timestamps of
+// its entries, normalizing the centroids like BuildCentroids does. Every entry must
+// be a valid box, otherwise the timestamps would no longer line up.
+func TrajectoryFromTraject(traject []interface{}, timestamps []int64, frameWidth, frameHeight float64) (Trajectory, error) {
+	boxes, err := DecodeTraject(traject)
+	if err != nil {
+		return Trajectory{}, err
+	}
+	return NewTrajectory(CentroidsFromBoxes(boxes, frameWidth, frameHeight), timestamps)
+}
+
+// Len returns the number of points in the trajectory.
+func (t Trajectory) Len() int {
+	return len(t.Centroids)
+}
+
+// Length returns the total path length.
+func (t Trajectory) Length() float64 {
+	return PathLength(t.Centroids)
+}
+
+// Duration returns the time between the first and the last point.
+func (t Trajectory) Duration() time.Duration {
+	if len(t.Timestamps) < 2 {
+		return 0
+	}
+	return time.Duration(t.Timestamps[len(t.Timestamps)-1]-t.Timestamps[0]) * time.Millisecond
+}
+
+// timed reports whether there is one timestamp per centroid.
+func (t Trajectory) timed() bool {
+	return len(t.Timestamps) == len(t.Centroids)
+}
+
+// segmentLength returns the distance between point i-1 and point i.
+func (t Trajectory) segmentLength(i int) float64 {
+	a, b := t.Centroids[i-1], t.Centroids[i]
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// segmentSeconds returns the time between point i-1 and point i in seconds.
+func (t Trajectory) segmentSeconds(i int) float64 {
+	return float64(t.Timestamps[i]-t.Timestamps[i-1]) / 1000
+}
+
+// Speeds returns the speed on each segment in units per second, so Speeds()[i] is
+// the speed between point i and point i+1. Segments without elapsed time have
+// speed 0.
+func (t Trajectory) Speeds() []float64 {
+	if t.Len() < 2 || !t.timed() {
+		return nil
+	}
+	speeds := make([]float64, t.Len()-1)
+	for i := 1; i < t.Len(); i++ {
+		if seconds := t.segmentSeconds(i); seconds > 0 {
+			speeds[i-1] = t.segmentLength(i) / seconds
+		}
+	}
+	return speeds
+}
+
+// AverageSpeed returns the path length divided by the duration, in units per
+// second.
+func (t Trajectory) AverageSpeed() float64 {
+	seconds := t.Duration().Seconds()
+	if seconds == 0 {
+		return 0
+	}
+	return t.Length() / seconds
+}
+
+// MaxSpeed returns the highest segment speed in units per second.
+func (t Trajectory) MaxSpeed() float64 {
+	maxSpeed := 0.0
+	for _, speed := range t.Speeds() {
+		maxSpeed = math.Max(maxSpeed, speed)
+	}
+	return maxSpeed
+}
+
+// Headings returns the heading of each segment in degrees in [0, 360), measured
+// clockwise on screen from the positive x axis: 0 is right and 90 is down.
+// Segments without movement have heading NaN.
+func (t Trajectory) Headings() []float64 {
+	if t.Len() < 2 {
+		return nil
+	}
+	headings := make([]float64, t.Len()-1)
+	for i := 1; i < t.Len(); i++ {
+		a, b := t.Centroids[i-1], t.Centroids[i]
+		if a == b {
+			headings[i-1] = math.NaN()
+			continue
+		}
+		heading := math.Atan2(b[1]-a[1], b[0]-a[0]) * 180 / math.Pi
+		if heading < 0 {
+			heading += 360
+		}
+		headings[i-1] = heading
+	}
+	return headings
+}
+
+// HeadingHistogram returns the distance travelled in each of bins equal heading
+// sectors. Bin 0 is centered on heading 0 (right) and the bins follow clockwise,
+// so with 8 bins they match the Direction constants.
+func (t Trajectory) HeadingHistogram(bins int) []float64 {
+	if bins <= 0 {
+		return nil
+	}
+	histogram := make([]float64, bins)
+	width := 360 / float64(bins)
+	for i, heading := range t.Headings() {
+		if math.IsNaN(heading) {
+			continue
+		}
+		bin := int(math.Floor(math.Mod(heading+width/2, 360) / width))
+		histogram[bin%bins] += t.segmentLength(i + 1)
+	}
+	return histogram
+}
+
+// DominantDirection returns the direction in which the object travelled the
+// longest distance. It returns false when the object did not move.
+func (t Trajectory) DominantDirection() (Direction, bool) {
+	histogram := t.HeadingHistogram(len(directions))
+	best := 0
+	for i, distance := range histogram {
+		if distance > histogram[best] {
+			best = i
+		}
+	}
+	if histogram[best] == 0 {
+		return "", false
+	}
+	return directions[best], true
+}
+
+// Stops returns the parts of the trajectory where the object stayed within radius
+// of the point where it stopped for at least minDuration.
+func (t Trajectory) Stops(radius float64, minDuration time.Duration) []Stop {
+	if !t.timed() {
+		return nil
+	}
+	var stops []Stop
+	for start := 0; start < t.Len(); {
+		end := start
+		for end+1 < t.Len() && PointFromArray(t.Centroids[end+1]).Distance(PointFromArray(t.Centroids[start])) <= radius {
+			end++
+		}
+		if end > start && time.Duration(t.Timestamps[end]-t.Timestamps[start])*time.Millisecond >= minDuration {
+			var sum Point
+			for _, c := range t.Centroids[start : end+1] {
+				sum = sum.Add(PointFromArray(c))
+			}
+			n := float64(end - start + 1)
+			stops = append(stops, Stop{
+				StartIndex: start,
+				EndIndex:   end,
+				Start:      t.Timestamps[start],
+				End:        t.Timestamps[end],
+				Position:   Point{X: sum.X / n, Y: sum.Y / n},
+			})
+			start = end + 1
+			continue
+		}
+		start++
+	}
+	return stops
+}
+
+// DwellTime returns how long the object spent inside zone. A segment with both
+// ends inside counts fully and a segment with one end inside counts for half.
+func (t Trajectory) DwellTime(zone Polygon) time.Duration {
+	if t.Len() < 2 || !t.timed() {
+		return 0
+	}
+	inside := make([]bool, t.Len())
+	for i, c := range t.Centroids {
+		inside[i] = zone.ContainsCentroid(c)
+	}
+	var milliseconds int64
+	for i := 1; i < t.Len(); i++ {
+		elapsed := t.Timestamps[i] - t.Timestamps[i-1]
+		switch {
+		case inside[i-1] && inside[i]:
+			milliseconds += 2 * elapsed
+		case inside[i-1] || inside[i]:
+			milliseconds += elapsed
+		}
+	}
+	return time.Duration(milliseconds) * time.Millisecond / 2
+}
+
+// MovingAverage returns a copy of the trajectory in which every centroid is the mean
+// of the window points centered on it. The window shrinks near the ends, so the
+// first and last points are kept. Timestamps are shared with t.
+func (t Trajectory) MovingAverage(window int) Trajectory {
+	half := window / 2
+	smoothed := make([][2]float64, t.Len())
+	for i := range t.Centroids {
+		reach := min(half, i, t.Len()-1-i)
+		var sum [2]float64
+		for _, c := range t.Centroids[i-reach : i+reach+1] {
+			sum[0] += c[0]
+			sum[1] += c[1]
+		}
+		n := float64(2*reach + 1)
+		smoothed[i] = [2]float64{sum[0] / n, sum[1] / n}
+	}
+	return Trajectory{Centroids: smoothed, Timestamps: t.Timestamps}
+}
+
+// Kalman returns a copy of the trajectory filtered with a constant velocity Kalman
+// filter on each axis. processNoise is the variance of the acceleration in units
+// per second squared and measurementNoise the variance of the detected positions;
+// a higher ratio of measurement to process noise smooths more. Timestamps are
+// shared with t.
+func (t Trajectory) Kalman(processNoise, measurementNoise float64) Trajectory {
+	if !t.timed() {
+		return Trajectory{}
+	}
+	filtered := make([][2]float64, t.Len())
+	for axis := 0; axis < 2; axis++ {
+		var filter kalmanAxis
+		for i, c := range t.Centroids {
+			if i == 0 {
+				filter = newKalmanAxis(c[axis], measurementNoise)
+			} else {
+				filter.predict(t.segmentSeconds(i), processNoise)
+				filter.update(c[axis], measurementNoise)
+			}
+			filtered[i][axis] = filter.position
+		}
+	}
+	return Trajectory{Centroids: filtered, Timestamps: t.Timestamps}
+}
+
+// kalmanAxis is the state of a constant velocity Kalman filter along one axis.
+type kalmanAxis struct {
+	position, velocity float64
+	// p is the covariance of (position, velocity).
+	p [2][2]float64
+}
+
+func newKalmanAxis(position, measurementNoise float64) kalmanAxis {
+	// The initial velocity is unknown, so it starts with a large variance.
+	return kalmanAxis{position: position, p: [2][2]float64{{measurementNoise, 0}, {0, 1e6}}}
+}
+
+func (k *kalmanAxis) predict(dt, processNoise float64) {
+	k.position += k.velocity * dt
+	p := k.p
+	k.p[0][0] = p[0][0] + dt*(p[1][0]+p[0][1]) + dt*dt*p[1][1] + processNoise*dt*dt*dt*dt/4
+	k.p[0][1] = p[0][1] + dt*p[1][1] + processNoise*dt*dt*dt/2
+	k.p[1][0] = k.p[0][1]
+	k.p[1][1] = p[1][1] + processNoise*dt*dt
+}
+
+func (k *kalmanAxis) update(measurement, measurementNoise float64) {
+	s := k.p[0][0] + measurementNoise
+	if s == 0 {
+		k.position = measurement
+		return
+	}
+	gain := [2]float64{k.p[0][0] / s, k.p[1][0] / s}
+	residual := measurement - k.position
+	k.position += gain[0] * residual
+	k.velocity += gain[1] * residual
+	p := k.p
+	k.p[0][0] = (1 - gain[0]) * p[0][0]
+	k.p[0][1] = (1 - gain[0]) * p[0][1]
+	k.p[1][0] = p[1][0] - gain[1]*p[0][0]
+	k.p[1][1] = p[1][1] - gain[1]*p[0][1]
+}
@@ -0,0 +1,229 @@
+package geometry
+
+import (
+	"encoding/json"
+	"math"
+	mrand "math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// walkAndWait moves right 10 units per second for 5 seconds, waits 3 seconds, then
+// moves down 10 units per second for 2 seconds, with one point per second.
+func walkAndWait(t *testing.T) Trajectory {
+	t.Helper()
+	centroids := [][2]float64{
+		{0, 0}, {10, 0}, {20, 0}, {30, 0}, {40, 0}, {50, 0},
+		{50, 0}, {50.5, 0}, {50, 0.5},
+		{50, 10}, {50, 20},
+	}
+	timestamps := make([]int64, len(centroids))
+	for i := range timestamps {
+		timestamps[i] = 1_700_000_000_000 + int64(i)*1000
+	}
+	trajectory, err := NewTrajectory(centroids, timestamps)
+	if err != nil {
+		t.Fatalf("NewTrajectory returned error: %v", err)
+	}
+	return trajectory
+}
+
+func TestNewTrajectory_Errors(t *testing.T) {
+	if _, err := NewTrajectory([][2]float64{{0, 0}}, []int64{1, 2}); err == nil {
+		t.Fatalf("expected error for mismatched lengths")
+	}
+	if _, err := NewTrajectory([][2]float64{{0, 0}, {1, 1}}, []int64{2, 1}); err == nil {
+		t.Fatalf("expected error for timestamps going back")
+	}
+}
+
+func TestTrajectory_MismatchedTimestamps(t *testing.T) {
+	// Decoding skips the checks of NewTrajectory.
+	var trajectory Trajectory
+	data := `{"centroids":[[0,0],[10,0],[20,0]],"timestamps":[0,1000]}`
+	if err := json.Unmarshal([]byte(data), &trajectory); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %v", err)
+	}
+	zone := NewPolygon([][2]float64{{-5, -5}, {25, -5}, {25, 5}, {-5, 5}})
+	if got := trajectory.Speeds(); got != nil {
+		t.Fatalf("Speeds: got %v, want nil", got)
+	}
+	if got := trajectory.MaxSpeed(); got != 0 {
+		t.Fatalf("MaxSpeed: got %v, want 0", got)
+	}
+	if got := trajectory.Stops(100, 0); got != nil {
+		t.Fatalf("Stops: got %v, want nil", got)
+	}
+	if got := trajectory.DwellTime(zone); got != 0 {
+		t.Fatalf("DwellTime: got %v, want 0", got)
+	}
+	if got := trajectory.Kalman(1, 1); got.Len() != 0 {
+		t.Fatalf("Kalman: got %v, want an empty trajectory", got)
+	}
+}
+
+func TestTrajectoryFromTraject(t *testing.T) {
+	traject := []interface{}{
+		[]interface{}{0.0, 0.0, 20.0, 20.0},
+		[]interface{}{180.0, 80.0, 200.0, 100.0},
+	}
+	trajectory, err := TrajectoryFromTraject(traject, []int64{0, 1000}, 200, 100)
+	if err != nil {
+		t.Fatalf("TrajectoryFromTraject returned error: %v", err)
+	}
+	want := [][2]float64{{5, 10}, {95, 90}}
+	if !reflect.DeepEqual(trajectory.Centroids, want) {
+		t.Fatalf("centroids: got %v, want %v", trajectory.Centroids, want)
+	}
+
+	malformed := []interface{}{[]interface{}{0.0, 0.0}, []interface{}{0.0, 0.0, 1.0, 1.0}}
+	if _, err := TrajectoryFromTraject(malformed, []int64{0, 1}, 200, 100); err == nil {
+		t.Fatalf("expected error for malformed traject")
+	}
+
+	// MongoDB decodes trajects as bson.A with integer coordinates.
+	decoded := []interface{}{
+		bson.A{int32(0), int32(0), int32(20), int32(20)},
+		bson.A{int64(180), int64(80), 200.0, 100.0},
+	}
+	trajectory, err = TrajectoryFromTraject(decoded, []int64{0, 1000}, 200, 100)
+	if err != nil {
+		t.Fatalf("TrajectoryFromTraject returned error for bson.A entries: %v", err)
+	}
+	if !reflect.DeepEqual(trajectory.Centroids, want) {
+		t.Fatalf("bson.A centroids: got %v, want %v", trajectory.Centroids, want)
+	}
+}
+
+func TestTrajectory_Metrics(t *testing.T) {
+	trajectory := walkAndWait(t)
+
+	wantLength := 50 + 0.5 + math.Hypot(0.5, 0.5) + 9.5 + 10
+	if got := trajectory.Length(); math.Abs(got-wantLength) > 1e-9 {
+		t.Fatalf("Length: got %v, want %v", got, wantLength)
+	}
+	if got := trajectory.Duration(); got != 10*time.Second {
+		t.Fatalf("Duration: got %v, want 10s", got)
+	}
+	if got := trajectory.AverageSpeed(); math.Abs(got-wantLength/10) > 1e-9 {
+		t.Fatalf("AverageSpeed: got %v, want %v", got, wantLength/10)
+	}
+	if got := trajectory.MaxSpeed(); got != 10 {
+		t.Fatalf("MaxSpeed: got %v, want 10", got)
+	}
+	if speeds := trajectory.Speeds(); len(speeds) != trajectory.Len()-1 || speeds[5] != 0 {
+		t.Fatalf("Speeds: got %v", speeds)
+	}
+}
+
+func TestTrajectory_Headings(t *testing.T) {
+	trajectory := Trajectory{
+		Centroids:  [][2]float64{{0, 0}, {1, 0}, {1, 1}, {1, 1}, {0, 1}, {0, 0}},
+		Timestamps: []int64{0, 1, 2, 3, 4, 5},
+	}
+	headings := trajectory.Headings()
+	if headings[0] != 0 || headings[1] != 90 || !math.IsNaN(headings[2]) || headings[3] != 180 || headings[4] != 270 {
+		t.Fatalf("Headings: got %v", headings)
+	}
+
+	histogram := trajectory.HeadingHistogram(4)
+	want := []float64{1, 1, 1, 1}
+	if !reflect.DeepEqual(histogram, want) {
+		t.Fatalf("HeadingHistogram(4): got %v, want %v", histogram, want)
+	}
+	if got := trajectory.HeadingHistogram(0); got != nil {
+		t.Fatalf("HeadingHistogram(0): got %v, want nil", got)
+	}
+}
+
+func TestTrajectory_DominantDirection(t *testing.T) {
+	if direction, ok := walkAndWait(t).DominantDirection(); !ok || direction != DirectionRight {
+		t.Fatalf("DominantDirection: got %q, %v, want right", direction, ok)
+	}
+	upLeft := Trajectory{Centroids: [][2]float64{{50, 50}, {40, 40}, {30, 31}}, Timestamps: []int64{0, 1, 2}}
+	if direction, _ := upLeft.DominantDirection(); direction != DirectionUpLeft {
+		t.Fatalf("DominantDirection: got %q, want up-left", direction)
+	}
+	still := Trajectory{Centroids: [][2]float64{{1, 1}, {1, 1}}, Timestamps: []int64{0, 1}}
+	if _, ok := still.DominantDirection(); ok {
+		t.Fatalf("DominantDirection of a still object should not be ok")
+	}
+}
+
+func TestTrajectory_Stops(t *testing.T) {
+	trajectory := walkAndWait(t)
+	stops := trajectory.Stops(1, 2*time.Second)
+	if len(stops) != 1 {
+		t.Fatalf("Stops: got %+v, want one stop", stops)
+	}
+	stop := stops[0]
+	if stop.StartIndex != 5 || stop.EndIndex != 8 || stop.Duration() != 3*time.Second {
+		t.Fatalf("Stops: got %+v", stop)
+	}
+	if math.Abs(stop.Position.X-50.125) > 1e-9 || math.Abs(stop.Position.Y-0.125) > 1e-9 {
+		t.Fatalf("Stops position: got %+v", stop.Position)
+	}
+	if stops := trajectory.Stops(1, 5*time.Second); len(stops) != 0 {
+		t.Fatalf("Stops with long minimum: got %+v", stops)
+	}
+}
+
+func TestTrajectory_DwellTime(t *testing.T) {
+	trajectory := walkAndWait(t)
+	zone := NewPolygon([][2]float64{{45, -5}, {55, -5}, {55, 5}, {45, 5}})
+	// Points 5 to 8 are inside: three full segments plus half of the segments
+	// entering and leaving.
+	if got := trajectory.DwellTime(zone); got != 4*time.Second {
+		t.Fatalf("DwellTime: got %v, want 4s", got)
+	}
+	outside := NewPolygon([][2]float64{{80, 80}, {90, 80}, {90, 90}})
+	if got := trajectory.DwellTime(outside); got != 0 {
+		t.Fatalf("DwellTime outside: got %v, want 0", got)
+	}
+}
+
+func TestTrajectory_MovingAverage(t *testing.T) {
+	trajectory := Trajectory{
+		Centroids:  [][2]float64{{0, 0}, {1, 3}, {2, 0}, {3, 3}, {4, 0}},
+		Timestamps: []int64{0, 1, 2, 3, 4},
+	}
+	smoothed := trajectory.MovingAverage(3)
+	want := [][2]float64{{0, 0}, {1, 1}, {2, 2}, {3, 1}, {4, 0}}
+	if !reflect.DeepEqual(smoothed.Centroids, want) {
+		t.Fatalf("MovingAverage(3): got %v, want %v", smoothed.Centroids, want)
+	}
+	if got := trajectory.MovingAverage(1); !reflect.DeepEqual(got.Centroids, trajectory.Centroids) {
+		t.Fatalf("MovingAverage(1): got %v", got.Centroids)
+	}
+}
+
+func TestTrajectory_Kalman(t *testing.T) {
+	rng := mrand.New(mrand.NewSource(1))
+	var centroids, truth [][2]float64
+	var timestamps []int64
+	for i := 0; i < 100; i++ {
+		position := [2]float64{float64(i) * 0.5, 20 + float64(i)*0.25}
+		truth = append(truth, position)
+		centroids = append(centroids, [2]float64{position[0] + rng.NormFloat64(), position[1] + rng.NormFloat64()})
+		timestamps = append(timestamps, int64(i)*100)
+	}
+	noisy := Trajectory{Centroids: centroids, Timestamps: timestamps}
+	filtered := noisy.Kalman(0.1, 1)
+
+	rmse := func(points [][2]float64) float64 {
+		sum := 0.0
+		for i := 20; i < len(points); i++ {
+			sum += math.Pow(points[i][0]-truth[i][0], 2) + math.Pow(points[i][1]-truth[i][1], 2)
+		}
+		return math.Sqrt(sum / float64(len(points)-20))
+	}
+	if before, after := rmse(noisy.Centroids), rmse(filtered.Centroids); after >= before/2 {
+		t.Fatalf("Kalman did not reduce the error enough: before %v, after %v", before, after)
+	}
+	if filtered.Length() >= noisy.Length() {
+		t.Fatalf("Kalman did not shorten the jittery path")
+	}
+}
@@ -0,0 +1,182 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+)
+
+// Transform is an affine transform mapping (x, y) to (A*x + B*y + C, D*x + E*y + F).
+// It converts coordinates between frame, normalized and display spaces. The zero
+// value maps everything to the origin; start from Identity instead.
+type Transform struct {
+	A, B, C float64
+	D, E, F float64
+}
+
+// Identity returns the transform that leaves points unchanged.
+func Identity() Transform {
+	return Transform{A: 1, E: 1}
+}
+
+// Translate returns a transform that moves points by (tx, ty).
+func Translate(tx, ty float64) Transform {
+	return Transform{A: 1, C: tx, E: 1, F: ty}
+}
+
+// Scale returns a transform that multiplies x by sx and y by sy.
+func Scale(sx, sy float64) Transform {
+	return Transform{A: sx, E: sy}
+}
+
+// Rotate returns a transform that rotates points around the origin by degrees,
+// clockwise on screen (y pointing down).
+func Rotate(degrees float64) Transform {
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	// Snap multiples of 90 degrees so quarter turns stay exact.
+	sin, cos = math.Round(sin*1e15)/1e15, math.Round(cos*1e15)/1e15
+	return Transform{A: cos, B: -sin, D: sin, E: cos}
+}
+
+// RotateAround returns a transform that rotates points around center by degrees,
+// clockwise on screen.
+func RotateAround(degrees float64, center Point) Transform {
+	return Translate(-center.X, -center.Y).Then(Rotate(degrees)).Then(Translate(center.X, center.Y))
+}
+
+// RotateFrame returns the transform from a width x height frame to the same frame
+// rotated by quarterTurns clockwise quarter turns, e.g. for cameras mounted
+// sideways. After an odd number of turns the frame is height x width.
+func RotateFrame(quarterTurns int, width, height float64) Transform {
+	switch ((quarterTurns % 4) + 4) % 4 {
+	case 1:
+		return Transform{B: -1, C: height, D: 1}
+	case 2:
+		return Transform{A: -1, C: width, E: -1, F: height}
+	case 3:
+		return Transform{B: 1, D: -1, F: width}
+	default:
+		return Identity()
+	}
+}
+
+// FlipHorizontal returns a transform that mirrors a frame of the given width.
+func FlipHorizontal(width float64) Transform {
+	return Transform{A: -1, C: width, E: 1}
+}
+
+// FlipVertical returns a transform that mirrors a frame of the given height upside
+// down.
+func FlipVertical(height float64) Transform {
+	return Transform{A: 1, E: -1, F: height}
+}
+
+// Crop returns the transform from frame coordinates to the coordinates of region
+// cropped out of the frame.
+func Crop(region BBox) Transform {
+	return Translate(-region.X1, -region.Y1)
+}
+
+// Letterbox returns the transform from a sourceWidth x sourceHeight frame to a
+// targetWidth x targetHeight viewport in which it is scaled to fit, keeping its
+// aspect ratio, and centered between bars.
+func Letterbox(sourceWidth, sourceHeight, targetWidth, targetHeight float64) Transform {
+	if sourceWidth <= 0 || sourceHeight <= 0 {
+		return Identity()
+	}
+	scale := math.Min(targetWidth/sourceWidth, targetHeight/sourceHeight)
+	return Scale(scale, scale).Then(Translate((targetWidth-sourceWidth*scale)/2, (targetHeight-sourceHeight*scale)/2))
+}
+
+// FrameToNormalized returns the transform from frame pixel coordinates into the
+// 0-100 space used by BuildCentroids. Like BuildCentroids, it leaves coordinates
+// unchanged when the frame size is unknown.
+func FrameToNormalized(frameWidth, frameHeight float64) Transform {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return Identity()
+	}
+	return Scale(100.0/frameWidth, 100.0/frameHeight)
+}
+
+// NormalizedToFrame returns the transform from the 0-100 space used by
+// BuildCentroids back into frame pixel coordinates.
+func NormalizedToFrame(frameWidth, frameHeight float64) Transform {
+	if frameWidth <= 0 || frameHeight <= 0 {
+		return Identity()
+	}
+	return Scale(frameWidth/100.0, frameHeight/100.0)
+}
+
+// Then returns the transform that applies t first and next second.
+func (t Transform) Then(next Transform) Transform {
+	return Transform{
+		A: next.A*t.A + next.B*t.D,
+		B: next.A*t.B + next.B*t.E,
+		C: next.A*t.C + next.B*t.F + next.C,
+		D: next.D*t.A + next.E*t.D,
+		E: next.D*t.B + next.E*t.E,
+		F: next.D*t.C + next.E*t.F + next.F,
+	}
+}
+
+// Determinant returns the factor by which the transform scales areas, negative when
+// it mirrors.
+func (t Transform) Determinant() float64 {
+	return t.A*t.E - t.B*t.D
+}
+
+// Invert returns the transform that undoes t. It fails when t collapses the plane,
+// e.g. a scale by 0.
+func (t Transform) Invert() (Transform, error) {
+	det := t.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Transform{}, fmt.Errorf("transform is not invertible")
+	}
+	return Transform{
+		A: t.E / det,
+		B: -t.B / det,
+		C: (t.B*t.F - t.E*t.C) / det,
+		D: -t.D / det,
+		E: t.A / det,
+		F: (t.D*t.C - t.A*t.F) / det,
+	}, nil
+}
+
+// Apply transforms a point.
+func (t Transform) Apply(p Point) Point {
+	return Point{X: t.A*p.X + t.B*p.Y + t.C, Y: t.D*p.X + t.E*p.Y + t.F}
+}
+
+// ApplyCentroids transforms centroids as returned by BuildCentroids.
+func (t Transform) ApplyCentroids(centroids [][2]float64) [][2]float64 {
+	transformed := make([][2]float64, len(centroids))
+	for i, c := range centroids {
+		transformed[i] = t.Apply(PointFromArray(c)).Array()
+	}
+	return transformed
+}
+
+// ApplyBBox transforms a box. Since a rotated box is no longer axis-aligned, the
+// result is the smallest box around the transformed corners.
+func (t Transform) ApplyBBox(b BBox) BBox {
+	corners := [4]Point{
+		t.Apply(Point{X: b.X1, Y: b.Y1}),
+		t.Apply(Point{X: b.X2, Y: b.Y1}),
+		t.Apply(Point{X: b.X2, Y: b.Y2}),
+		t.Apply(Point{X: b.X1, Y: b.Y2}),
+	}
+	result := BBox{X1: corners[0].X, Y1: corners[0].Y, X2: corners[0].X, Y2: corners[0].Y}
+	for _, c := range corners[1:] {
+		result.X1, result.X2 = math.Min(result.X1, c.X), math.Max(result.X2, c.X)
+		result.Y1, result.Y2 = math.Min(result.Y1, c.Y), math.Max(result.Y2, c.Y)
+	}
+	return result
+}
+
+// ApplyPolygon transforms every vertex of a polygon.
+func (t Transform) ApplyPolygon(p Polygon) Polygon {
+	transformed := make(Polygon, len(p))
+	for i, v := range p {
+		transformed[i] = t.Apply(v)
+	}
+	return transformed
+}
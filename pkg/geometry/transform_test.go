@@ -0,0 +1,127 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+)
+
+func pointsClose(a, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestTransform_Apply(t *testing.T) {
+	p := Point{X: 10, Y: 20}
+	tests := []struct {
+		name      string
+		transform Transform
+		want      Point
+	}{
+		{"identity", Identity(), Point{10, 20}},
+		{"translate", Translate(5, -5), Point{15, 15}},
+		{"scale", Scale(2, 0.5), Point{20, 10}},
+		{"rotate clockwise on screen", Rotate(90), Point{-20, 10}},
+		{"rotate around", RotateAround(180, Point{10, 10}), Point{10, 0}},
+		{"flip horizontal", FlipHorizontal(100), Point{90, 20}},
+		{"flip vertical", FlipVertical(100), Point{10, 80}},
+		{"crop", Crop(BBox{X1: 5, Y1: 5, X2: 50, Y2: 50}), Point{5, 15}},
+		{"frame to normalized", FrameToNormalized(200, 400), Point{5, 5}},
+		{"normalized to frame", NormalizedToFrame(200, 400), Point{20, 80}},
+		{"unknown frame size", FrameToNormalized(0, 0), Point{10, 20}},
+		{"then", Scale(2, 2).Then(Translate(1, 1)), Point{21, 41}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.transform.Apply(p); !pointsClose(got, tt.want) {
+				t.Fatalf("Apply(%v): got %v, want %v", p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateFrame(t *testing.T) {
+	// A 200x100 frame; its top-right corner after each clockwise quarter turn.
+	corner := Point{X: 200, Y: 0}
+	want := []Point{{200, 0}, {100, 200}, {0, 100}, {0, 0}, {200, 0}, {0, 0}}
+	for i, turns := range []int{0, 1, 2, 3, 4, -1} {
+		if got := RotateFrame(turns, 200, 100).Apply(corner); !pointsClose(got, want[i]) {
+			t.Fatalf("RotateFrame(%d): got %v, want %v", turns, got, want[i])
+		}
+	}
+	// A quarter turn maps the whole frame onto the rotated frame.
+	if got := RotateFrame(1, 200, 100).ApplyBBox(Frame(200, 100)); got != Frame(100, 200) {
+		t.Fatalf("RotateFrame(1) frame: got %v", got)
+	}
+}
+
+func TestLetterbox(t *testing.T) {
+	// A 16:9 stream in a 4:3 player gets bars above and below.
+	letterbox := Letterbox(1920, 1080, 800, 600)
+	frame := letterbox.ApplyBBox(Frame(1920, 1080))
+	want := BBox{X1: 0, Y1: 75, X2: 800, Y2: 525}
+	if !pointsClose(Point{frame.X1, frame.Y1}, Point{want.X1, want.Y1}) || !pointsClose(Point{frame.X2, frame.Y2}, Point{want.X2, want.Y2}) {
+		t.Fatalf("Letterbox frame: got %v, want %v", frame, want)
+	}
+
+	// From the normalized space of BuildCentroids to the player and back.
+	toPlayer := NormalizedToFrame(1920, 1080).Then(letterbox)
+	if got := toPlayer.Apply(Point{50, 50}); !pointsClose(got, Point{400, 300}) {
+		t.Fatalf("center in player: got %v", got)
+	}
+	fromPlayer, err := toPlayer.Invert()
+	if err != nil {
+		t.Fatalf("Invert returned error: %v", err)
+	}
+	if got := fromPlayer.Apply(Point{800, 525}); !pointsClose(got, Point{100, 100}) {
+		t.Fatalf("player corner normalized: got %v", got)
+	}
+}
+
+func TestTransform_Invert(t *testing.T) {
+	transform := Scale(3, 2).Then(Rotate(30)).Then(FlipHorizontal(50)).Then(Translate(7, -3))
+	inverse, err := transform.Invert()
+	if err != nil {
+		t.Fatalf("Invert returned error: %v", err)
+	}
+	for _, p := range []Point{{0, 0}, {12.5, -4}, {100, 100}} {
+		if got := inverse.Apply(transform.Apply(p)); !pointsClose(got, p) {
+			t.Fatalf("round trip of %v: got %v", p, got)
+		}
+	}
+	if composed := transform.Then(inverse); !pointsClose(composed.Apply(Point{3, 4}), Point{3, 4}) {
+		t.Fatalf("transform then inverse is not the identity: %+v", composed)
+	}
+	if transform.Determinant() >= 0 {
+		t.Fatalf("Determinant of a mirroring transform should be negative")
+	}
+	if _, err := Scale(0, 1).Invert(); err == nil {
+		t.Fatalf("expected error inverting a degenerate transform")
+	}
+}
+
+func TestTransform_ApplyShapes(t *testing.T) {
+	toNormalized := FrameToNormalized(200, 100)
+
+	box := BBox{X1: 20, Y1: 10, X2: 60, Y2: 50}
+	if got := toNormalized.ApplyBBox(box); got != box.Normalize(200, 100) {
+		t.Fatalf("ApplyBBox: got %v, want %v", got, box.Normalize(200, 100))
+	}
+	if got := FlipHorizontal(200).ApplyBBox(box); got != (BBox{X1: 140, Y1: 10, X2: 180, Y2: 50}) {
+		t.Fatalf("ApplyBBox flipped: got %v", got)
+	}
+	rotated := RotateAround(45, box.Center()).ApplyBBox(box)
+	if math.Abs(rotated.Width()-40*math.Sqrt2) > 1e-9 || !pointsClose(rotated.Center(), box.Center()) {
+		t.Fatalf("ApplyBBox rotated: got %v", rotated)
+	}
+
+	zone := NewPolygon([][2]float64{{0, 0}, {200, 0}, {200, 100}})
+	if got := toNormalized.ApplyPolygon(zone); got.Area() != zone.Normalize(200, 100).Area() {
+		t.Fatalf("ApplyPolygon: got %v", got)
+	}
+
+	centroids := [][2]float64{{0, 0}, {100, 50}}
+	got := toNormalized.ApplyCentroids(centroids)
+	if got[1] != [2]float64{50, 50} || centroids[1] != [2]float64{100, 50} {
+		t.Fatalf("ApplyCentroids: got %v, input %v", got, centroids)
+	}
+}